@@ -2,90 +2,214 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"wkit/internal/config"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
+// addRecord is the machine-readable result of adding a worktree
+type addRecord struct {
+	Branch      string   `json:"branch" yaml:"branch"`
+	Path        string   `json:"path" yaml:"path"`
+	BaseBranch  string   `json:"base_branch,omitempty" yaml:"base_branch,omitempty"`
+	Action      string   `json:"action" yaml:"action"` // created, reused, detached or existing
+	CopiedFiles []string `json:"copied_files" yaml:"copied_files"`
+}
+
 func NewAddCmd() *cobra.Command {
+	var format string
+
 	cmd := &cobra.Command{
-		Use:   "add <branch> [path]",
-		Short: "Add a new worktree",
-		Args:  cobra.RangeArgs(1, 2), // branch (required), path (optional)
+		Use:               "add <branch> [path]",
+		Short:             "Add a new worktree",
+		Args:              cobra.RangeArgs(1, 2), // branch (required), path (optional)
+		ValidArgsFunction: completeAddBranch,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			branch := args[0]
-			var worktreePath string
-
-			manager, err := worktree.NewManager()
-			if err != nil {
-				return fmt.Errorf("failed to create manager: %w", err)
-			}
-
-			if len(args) > 1 {
-				worktreePath = args[1]
-			} else {
-				cfg, err := config.Load()
-				if err != nil {
-					return fmt.Errorf("failed to load config: %w", err)
-				}
-				repoRoot, err := worktree.GetRepositoryRoot()
-				if err != nil {
-					return fmt.Errorf("failed to get repository root: %w", err)
-				}
-				worktreePath = cfg.ResolveWorktreePath(branch, "", repoRoot)
-			}
 
 			noSwitch, _ := cmd.Flags().GetBool("no-switch")
 			baseBranch, _ := cmd.Flags().GetString("base-branch")
+			onConflict, _ := cmd.Flags().GetString("on-conflict")
+			forceDuplicate, _ := cmd.Flags().GetBool("force-duplicate")
 
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+			switch onConflict {
+			case "fail", "suffix", "reuse":
+			default:
+				return fmt.Errorf("invalid --on-conflict value: %s. Valid values: fail, suffix, reuse", onConflict)
+			}
+			if err := output.Validate(format); err != nil {
+				return err
 			}
 
-			// Use provided base branch or fall back to config main branch
-			if baseBranch == "" {
-				baseBranch = cfg.MainBranch
+			// Keep stdout machine-readable by sending progress messages to stderr
+			var msg io.Writer = os.Stdout
+			if !output.IsTable(format) {
+				msg = os.Stderr
 			}
 
-			err = manager.AddWorktree(branch, worktreePath, baseBranch)
+			manager, err := worktree.NewManager()
 			if err != nil {
-				return fmt.Errorf("failed to add worktree: %w", err)
+				return fmt.Errorf("failed to create manager: %w", err)
 			}
 
-			fmt.Printf("✓ Created worktree for branch '%s' at '%s'\n", branch, worktreePath)
-
-			// Copy configured files if enabled
-			repoRoot, err := worktree.GetRepositoryRoot()
+			cfg, err := config.Load()
 			if err != nil {
-				return fmt.Errorf("failed to get repository root: %w", err)
+				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			copiedFiles, err := cfg.CopyFilesToWorktree(repoRoot, worktreePath)
+			opts := addOptions{branch: branch, baseBranch: baseBranch, onConflict: onConflict, forceDuplicate: forceDuplicate}
+			if len(args) > 1 {
+				opts.path = args[1]
+			}
+			record, err := createWorktree(manager, cfg, opts, msg)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to copy files: %v\n", err)
-			} else if len(copiedFiles) > 0 {
-				fmt.Printf("✓ Copied files: %v\n", copiedFiles)
+				return err
 			}
 
+			// Recorded whatever the format, so 'switch -' works after scripted adds too
 			if !noSwitch {
-				relativePath, err := worktree.GetRelativePathFromRoot()
-				if err != nil {
-					// If we can't get relative path, just output the worktree path
-					fmt.Println(worktreePath)
-				} else if relativePath != "" {
-					fmt.Printf("%s:%s\n", worktreePath, relativePath)
-				} else {
-					fmt.Println(worktreePath)
-				}
+				recordSwitch(manager, record.Path)
 			}
-			return nil
+			return output.Write(cmd.OutOrStdout(), format, record, func(w io.Writer) error {
+				if !noSwitch {
+					printSwitchTarget(w, record.Path)
+				}
+				return nil
+			})
 		},
 	}
 
 	cmd.Flags().Bool("no-switch", false, "Skip automatic switching to new worktree")
 	cmd.Flags().StringP("base-branch", "b", "", "Base branch to create new branch from (defaults to config main_branch)")
+	cmd.Flags().String("on-conflict", "fail", "What to do when the target path exists: fail, suffix, reuse")
+	cmd.Flags().Bool("force-duplicate", false, "Create a detached worktree when the branch is already checked out elsewhere")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
+	cmd.RegisterFlagCompletionFunc("base-branch", completeBranches)
+	cmd.RegisterFlagCompletionFunc("on-conflict", cobra.FixedCompletions([]string{"fail", "suffix", "reuse"}, cobra.ShellCompDirectiveNoFileComp))
 	return cmd
 }
+
+// addOptions are the settings for creating a worktree shared by add and switch --create
+type addOptions struct {
+	branch         string
+	path           string // resolved from wkit_root and path_template when empty
+	baseBranch     string // defaults to config main_branch
+	onConflict     string // fail, suffix or reuse
+	forceDuplicate bool
+}
+
+// createWorktree adds a worktree for opts.branch and copies the configured files into it,
+// writing progress to msg. When the branch is already checked out and opts.forceDuplicate
+// is not set, it points at the existing worktree instead.
+func createWorktree(manager *worktree.Manager, cfg *config.Config, opts addOptions, msg io.Writer) (addRecord, error) {
+	branch := opts.branch
+
+	repoRoot, err := worktree.GetRepositoryRoot()
+	if err != nil {
+		return addRecord{}, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	worktreePath := opts.path
+	if worktreePath == "" {
+		worktreePath, err = cfg.ResolveWkitPath(branch, "", repoRoot)
+		if err != nil {
+			return addRecord{}, fmt.Errorf("failed to resolve worktree path: %w", err)
+		}
+	}
+
+	// Use provided base branch or fall back to config main branch
+	baseBranch := opts.baseBranch
+	if baseBranch == "" {
+		baseBranch = cfg.MainBranch
+	}
+
+	existing, err := manager.FindWorktreeByBranch(branch)
+	if err != nil {
+		return addRecord{}, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if existing != nil {
+		if !opts.forceDuplicate {
+			// Git refuses to check out a branch twice, so point at the existing worktree instead
+			fmt.Fprintf(os.Stderr, "Branch '%s' is already checked out at '%s'\n", branch, existing.Path)
+			visitIndex(manager, existing.Path)
+			return addRecord{Branch: branch, Path: existing.Path, Action: "existing", CopiedFiles: []string{}}, nil
+		}
+		if worktreePath == existing.Path {
+			worktreePath = nextAvailablePath(worktreePath)
+		}
+	}
+
+	reuse := false
+	if pathExists(worktreePath) && !isEmptyDir(worktreePath) {
+		orphaned := manager.IsOrphanedCheckoutOf(worktreePath, branch)
+		switch {
+		case opts.onConflict == "suffix":
+			worktreePath = nextAvailablePath(worktreePath)
+		case opts.onConflict == "reuse" && orphaned:
+			reuse = true
+		case opts.onConflict == "reuse":
+			return addRecord{}, fmt.Errorf("path '%s' already exists and is not an orphaned checkout of '%s'", worktreePath, branch)
+		case orphaned:
+			return addRecord{}, fmt.Errorf("path '%s' already exists and holds an orphaned checkout of '%s'; use --on-conflict=reuse to adopt it or --on-conflict=suffix to use another path", worktreePath, branch)
+		default:
+			return addRecord{}, fmt.Errorf("path '%s' already exists; use --on-conflict=suffix to use another path", worktreePath)
+		}
+	}
+
+	record := addRecord{Branch: branch, Path: worktreePath, BaseBranch: baseBranch}
+	if existing != nil {
+		err = manager.AddDetachedWorktree(worktreePath, branch)
+		if err != nil {
+			return addRecord{}, fmt.Errorf("failed to add worktree: %w", err)
+		}
+		record.Action = "detached"
+		record.BaseBranch = ""
+		fmt.Fprintf(msg, "✓ Created detached worktree at '%s' from branch '%s'\n", worktreePath, branch)
+	} else if reuse {
+		err = manager.AdoptWorktree(worktreePath, branch)
+		if err != nil {
+			return addRecord{}, fmt.Errorf("failed to reuse worktree: %w", err)
+		}
+		record.Action = "reused"
+		record.BaseBranch = ""
+		fmt.Fprintf(msg, "✓ Reused existing checkout of branch '%s' at '%s'\n", branch, worktreePath)
+	} else {
+		err = manager.AddWorktree(branch, worktreePath, baseBranch)
+		if err != nil {
+			return addRecord{}, fmt.Errorf("failed to add worktree: %w", err)
+		}
+		record.Action = "created"
+		fmt.Fprintf(msg, "✓ Created worktree for branch '%s' at '%s'\n", branch, worktreePath)
+	}
+
+	// Copy configured files if enabled
+	copiedFiles, err := cfg.CopyFilesToWorktree(repoRoot, worktreePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to copy files: %v\n", err)
+	} else if len(copiedFiles) > 0 {
+		fmt.Fprintf(msg, "✓ Copied files: %v\n", copiedFiles)
+	}
+	record.CopiedFiles = copiedFiles
+	visitIndex(manager, worktreePath)
+
+	return record, nil
+}
+
+// nextAvailablePath returns the first of path-2, path-3, ... that does not exist yet
+func nextAvailablePath(path string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", path, i)
+		if !pathExists(candidate) {
+			return candidate
+		}
+	}
+}
+
+func isEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err == nil && len(entries) == 0
+}
@@ -290,3 +290,22 @@ func TestCopyDirectoryIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestNextAvailablePath(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "feature")
+
+	if result := nextAvailablePath(base); result != base+"-2" {
+		t.Errorf("nextAvailablePath() = %s, want %s", result, base+"-2")
+	}
+
+	for _, dir := range []string{base + "-2", base + "-3"} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	if result := nextAvailablePath(base); result != base+"-4" {
+		t.Errorf("nextAvailablePath() = %s, want %s", result, base+"-4")
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/worktree"
+)
+
+func NewAdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt <dir>",
+		Short: "Re-register an orphaned checkout as a worktree",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to resolve path: %w", err)
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			branch, _ := cmd.Flags().GetString("branch")
+			if branch == "" {
+				branch = manager.CheckoutBranch(path)
+			}
+			if branch == "" {
+				cfg, err := config.Load()
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				// path_template names directories after slugs, which cannot be mapped back
+				if cfg.PathTemplate != "" {
+					return fmt.Errorf("cannot determine the branch of '%s' with a path_template configured; pass it with --branch", path)
+				}
+				repoRoot, err := worktree.GetRepositoryRoot()
+				if err != nil {
+					return fmt.Errorf("failed to get repository root: %w", err)
+				}
+				// Directories under wkit_root are named after their branch
+				branch = worktree.BranchFromWkitPath(cfg.ResolveWkitRoot(repoRoot), path)
+			}
+
+			err = manager.AdoptWorktree(path, branch)
+			if err != nil {
+				return fmt.Errorf("failed to adopt worktree: %w", err)
+			}
+
+			fmt.Printf("✓ Adopted worktree at '%s'\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("branch", "b", "", "Branch of the checkout (defaults to the branch it has checked out, then the path under wkit_root)")
+	return cmd
+}
@@ -2,21 +2,44 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"wkit/internal/config"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
+// cleanRecord is the machine-readable result for one unnecessary worktree
+type cleanRecord struct {
+	Path    string `json:"path" yaml:"path"`
+	Branch  string `json:"branch" yaml:"branch"`
+	Reason  string `json:"reason" yaml:"reason"`
+	Removed bool   `json:"removed" yaml:"removed"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 func NewCleanCmd() *cobra.Command {
+	var format string
+
 	cmd := &cobra.Command{
 		Use:   "clean",
 		Short: "Clean up unnecessary worktrees",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
 
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
+			// Keep stdout machine-readable by sending progress messages to stderr
+			var msg io.Writer = os.Stdout
+			if !output.IsTable(format) {
+				msg = os.Stderr
+			}
+
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
@@ -32,38 +55,56 @@ func NewCleanCmd() *cobra.Command {
 				return fmt.Errorf("failed to find unnecessary worktrees: %w", err)
 			}
 
+			records := make([]cleanRecord, 0, len(unnecessaryWorktrees))
+			for _, uw := range unnecessaryWorktrees {
+				records = append(records, cleanRecord{
+					Path:   uw.Worktree.Path,
+					Branch: uw.Worktree.Branch,
+					Reason: uw.Reason,
+				})
+			}
+			writeRecords := func() error {
+				return output.Write(cmd.OutOrStdout(), format, records, func(w io.Writer) error {
+					return nil
+				})
+			}
+
 			if len(unnecessaryWorktrees) == 0 {
-				fmt.Println("No unnecessary worktrees found.")
-				return nil
+				fmt.Fprintln(msg, "No unnecessary worktrees found.")
+				return writeRecords()
 			}
 
-			fmt.Printf("Found %d unnecessary worktree(s):\n", len(unnecessaryWorktrees))
+			fmt.Fprintf(msg, "Found %d unnecessary worktree(s):\n", len(unnecessaryWorktrees))
 			for _, uw := range unnecessaryWorktrees {
-				fmt.Printf("  %s - %s\n", uw.Worktree.Path, uw.Reason)
+				fmt.Fprintf(msg, "  %s - %s\n", uw.Worktree.Path, uw.Reason)
 			}
 
 			if !force {
-				fmt.Print("\nRemove these worktrees? (y/N): ")
+				fmt.Fprint(msg, "\nRemove these worktrees? (y/N): ")
 				var confirm string
 				fmt.Scanln(&confirm)
 				if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
-					fmt.Println("Cancelled.")
-					return nil
+					fmt.Fprintln(msg, "Cancelled.")
+					return writeRecords()
 				}
 			}
 
-			for _, uw := range unnecessaryWorktrees {
+			for i, uw := range unnecessaryWorktrees {
 				err := manager.RemoveWorktree(uw.Worktree.Path)
 				if err != nil {
+					records[i].Error = err.Error()
 					fmt.Fprintf(os.Stderr, "Error removing worktree %s: %v\n", uw.Worktree.Path, err)
 					continue
 				}
-				fmt.Printf("✓ Removed worktree at '%s'\n", uw.Worktree.Path)
+				records[i].Removed = true
+				forgetIndex(uw.Worktree.Path)
+				fmt.Fprintf(msg, "✓ Removed worktree at '%s'\n", uw.Worktree.Path)
 			}
-			return nil
+			return writeRecords()
 		},
 	}
 
 	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
 	return cmd
 }
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/worktree"
+)
+
+func NewCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate the shell completion script",
+		Long: `Generate the completion script for wkit. Worktree names, branch names and
+configuration keys are completed dynamically.
+
+  bash:       source <(wkit completion bash)
+  zsh:        source <(wkit completion zsh)
+  fish:       wkit completion fish | source
+  powershell: wkit completion powershell | Out-String | Invoke-Expression
+
+'wkit shell-init' already includes completions for bash, zsh and fish.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeCompletion(cmd.Root(), cmd.OutOrStdout(), args[0])
+		},
+	}
+}
+
+// writeCompletion writes the completion script of root for shell
+func writeCompletion(root *cobra.Command, out io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(out)
+	}
+	return fmt.Errorf("unsupported shell: %s. Valid values: bash, zsh, fish, powershell", shell)
+}
+
+// completeWorktrees completes the first argument with worktree branch names, or with the
+// path of worktrees on a detached HEAD
+func completeWorktrees(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return worktreeCompletions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeWorktreeList completes any number of worktree arguments, skipping those already given
+func completeWorktreeList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		given[arg] = true
+	}
+	var completions []string
+	for _, completion := range worktreeCompletions() {
+		name, _, _ := strings.Cut(completion, "\t")
+		if !given[name] {
+			completions = append(completions, completion)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func worktreeCompletions() []string {
+	manager, err := worktree.NewManager()
+	if err != nil {
+		return nil
+	}
+	worktrees, err := manager.ListWorktrees()
+	if err != nil {
+		return nil
+	}
+	repoRoot, err := worktree.GetRepositoryRoot()
+	if err != nil {
+		repoRoot = ""
+	}
+
+	var completions []string
+	for _, wt := range worktrees {
+		name := wt.Branch
+		if name == "" {
+			name = wt.Path
+		}
+		description := wt.Path
+		if repoRoot != "" {
+			description = relativeToRoot(repoRoot, wt.Path)
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s", name, description))
+	}
+	return completions
+}
+
+// completeAddBranch completes the branch argument of add with local branches and the
+// branches of remotes, without the remote prefix
+func completeAddBranch(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		// The optional second argument is a path
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+
+	manager, err := worktree.NewManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	local, remote, err := manager.ListBranches()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, branch := range local {
+		seen[branch] = true
+		completions = append(completions, branch+"\tlocal branch")
+	}
+	for _, ref := range remote {
+		_, branch, ok := strings.Cut(ref, "/")
+		if !ok || seen[branch] {
+			continue
+		}
+		seen[branch] = true
+		completions = append(completions, branch+"\t"+ref)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBranches completes local and remote-tracking branch names
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	manager, err := worktree.NewManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	local, remote, err := manager.ListBranches()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return append(local, remote...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// configKeys are the keys accepted by config set
+var configKeys = []string{
+	"wkit_root\tDirectory where worktrees are created",
+	"path_template\tGo template for worktree paths",
+	"auto_cleanup\tRemove merged worktrees automatically",
+	"default_sync_strategy\tmerge or rebase",
+	"main_branch\tBranch that sync and clean compare against",
+	"copy_files.enabled\tCopy files into new worktrees",
+	"copy_files.files\tComma-separated files to copy",
+	"status.parallelism\tWorktrees inspected concurrently (0 = one per CPU)",
+	"status.timeout\tPer-worktree status timeout",
+	"switch.create_missing\tCreate missing worktrees on switch",
+}
+
+// completeConfigSet completes the key and then the value of config set
+func completeConfigSet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return configKeys, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		if args[0] == "main_branch" {
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			local, _, err := manager.ListBranches()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return local, cobra.ShellCompDirectiveNoFileComp
+		}
+		if args[0] == "wkit_root" {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return configValueCompletions(args[0]), cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// configValueCompletions returns the values suggested for a config key
+func configValueCompletions(key string) []string {
+	switch key {
+	case "auto_cleanup", "copy_files.enabled", "switch.create_missing":
+		return []string{"true", "false"}
+	case "default_sync_strategy":
+		return []string{"merge", "rebase"}
+	case "path_template":
+		return []string{
+			"{{.Repo}}/{{.BranchSlug}}\tper repository",
+			"{{.BranchSlug}}\tflat",
+			"{{.User}}/{{.BranchSlug}}\tper user",
+		}
+	case "status.parallelism":
+		return []string{"0\tone per CPU", "4", "8"}
+	case "status.timeout":
+		return []string{"5s", "10s", "30s"}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfigValueCompletions(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"auto_cleanup", []string{"true", "false"}},
+		{"copy_files.enabled", []string{"true", "false"}},
+		{"default_sync_strategy", []string{"merge", "rebase"}},
+		{"copy_files.files", nil},
+		{"unknown", nil},
+	}
+
+	for _, tt := range tests {
+		if got := configValueCompletions(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("configValueCompletions(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	root := &cobra.Command{Use: "wkit"}
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var buf bytes.Buffer
+		if err := writeCompletion(root, &buf, shell); err != nil {
+			t.Errorf("writeCompletion(%q) failed: %v", shell, err)
+		} else if !strings.Contains(buf.String(), "wkit") {
+			t.Errorf("writeCompletion(%q) does not mention wkit", shell)
+		}
+	}
+
+	if err := writeCompletion(root, &bytes.Buffer{}, "tcsh"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
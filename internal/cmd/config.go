@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"wkit/internal/config"
+	"wkit/internal/output"
 )
 
 func NewConfigCmd() *cobra.Command {
@@ -22,33 +26,82 @@ func NewConfigCmd() *cobra.Command {
 	return configCmd
 }
 
+// configRecord is the machine-readable form of the configuration, keyed like the config file
+type configRecord struct {
+	WkitRoot            string `json:"wkit_root" yaml:"wkit_root"`
+	PathTemplate        string `json:"path_template" yaml:"path_template"`
+	AutoCleanup         bool   `json:"auto_cleanup" yaml:"auto_cleanup"`
+	DefaultSyncStrategy string `json:"default_sync_strategy" yaml:"default_sync_strategy"`
+	MainBranch          string `json:"main_branch" yaml:"main_branch"`
+	CopyFiles           struct {
+		Enabled bool     `json:"enabled" yaml:"enabled"`
+		Files   []string `json:"files" yaml:"files"`
+	} `json:"copy_files" yaml:"copy_files"`
+	Status struct {
+		Parallelism int    `json:"parallelism" yaml:"parallelism"`
+		Timeout     string `json:"timeout" yaml:"timeout"`
+	} `json:"status" yaml:"status"`
+	Switch struct {
+		CreateMissing bool `json:"create_missing" yaml:"create_missing"`
+	} `json:"switch" yaml:"switch"`
+}
+
 func NewConfigShowCmd() *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show current configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			fmt.Println("Current configuration:")
-			fmt.Printf("  wkit_root: %s\n", cfg.WkitRoot)
-			fmt.Printf("  auto_cleanup: %t\n", cfg.AutoCleanup)
-			fmt.Printf("  default_sync_strategy: %s\n", cfg.DefaultSyncStrategy)
-			fmt.Printf("  main_branch: %s\n", cfg.MainBranch)
-			fmt.Printf("  copy_files.enabled: %t\n", cfg.CopyFiles.Enabled)
-			fmt.Printf("  copy_files.files: %v\n", cfg.CopyFiles.Files)
-			return nil
+			record := configRecord{
+				WkitRoot:            cfg.WkitRoot,
+				PathTemplate:        cfg.PathTemplate,
+				AutoCleanup:         cfg.AutoCleanup,
+				DefaultSyncStrategy: cfg.DefaultSyncStrategy,
+				MainBranch:          cfg.MainBranch,
+			}
+			record.CopyFiles.Enabled = cfg.CopyFiles.Enabled
+			record.CopyFiles.Files = cfg.CopyFiles.Files
+			record.Status.Parallelism = cfg.Status.Parallelism
+			record.Status.Timeout = cfg.Status.Timeout.String()
+			record.Switch.CreateMissing = cfg.Switch.CreateMissing
+
+			return output.Write(cmd.OutOrStdout(), format, record, func(w io.Writer) error {
+				fmt.Fprintln(w, "Current configuration:")
+				fmt.Fprintf(w, "  wkit_root: %s\n", cfg.WkitRoot)
+				fmt.Fprintf(w, "  path_template: %s\n", cfg.PathTemplate)
+				fmt.Fprintf(w, "  auto_cleanup: %t\n", cfg.AutoCleanup)
+				fmt.Fprintf(w, "  default_sync_strategy: %s\n", cfg.DefaultSyncStrategy)
+				fmt.Fprintf(w, "  main_branch: %s\n", cfg.MainBranch)
+				fmt.Fprintf(w, "  copy_files.enabled: %t\n", cfg.CopyFiles.Enabled)
+				fmt.Fprintf(w, "  copy_files.files: %v\n", cfg.CopyFiles.Files)
+				fmt.Fprintf(w, "  status.parallelism: %d\n", cfg.Status.Parallelism)
+				fmt.Fprintf(w, "  status.timeout: %s\n", cfg.Status.Timeout)
+				fmt.Fprintf(w, "  switch.create_missing: %t\n", cfg.Switch.CreateMissing)
+				return nil
+			})
 		},
 	}
+
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
+	return cmd
 }
 
 func NewConfigSetCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "set <key> <value>",
-		Short: "Set a configuration value",
-		Args:  cobra.ExactArgs(2),
+		Use:               "set <key> <value>",
+		Short:             "Set a configuration value",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeConfigSet,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
 			value := args[1]
@@ -61,6 +114,13 @@ func NewConfigSetCmd() *cobra.Command {
 			switch key {
 			case "wkit_root":
 				cfg.WkitRoot = value
+			case "path_template":
+				if value != "" {
+					if _, err := config.ExecutePathTemplate(value, config.NewPathTemplateData("example", "repo")); err != nil {
+						return err
+					}
+				}
+				cfg.PathTemplate = value
 			case "auto_cleanup":
 				b, err := parseBool(value)
 				if err != nil {
@@ -82,6 +142,24 @@ func NewConfigSetCmd() *cobra.Command {
 				cfg.CopyFiles.Enabled = b
 			case "copy_files.files":
 				cfg.CopyFiles.Files = strings.Split(value, ",")
+			case "status.parallelism":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return fmt.Errorf("invalid value for status.parallelism: %s. Must be a non-negative integer", value)
+				}
+				cfg.Status.Parallelism = n
+			case "status.timeout":
+				d, err := time.ParseDuration(value)
+				if err != nil {
+					return fmt.Errorf("invalid duration for status.timeout: %w", err)
+				}
+				cfg.Status.Timeout = d
+			case "switch.create_missing":
+				b, err := parseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid boolean value for switch.create_missing: %w", err)
+				}
+				cfg.Switch.CreateMissing = b
 			default:
 				return fmt.Errorf("unknown configuration key: %s", key)
 			}
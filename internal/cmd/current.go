@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/output"
+	"wkit/internal/worktree"
+)
+
+// currentRecord is the machine-readable form of the current worktree
+type currentRecord struct {
+	Branch string `json:"branch" yaml:"branch"` // empty on a detached HEAD
+	Path   string `json:"path" yaml:"path"`
+	HEAD   string `json:"head" yaml:"head"`
+	Main   bool   `json:"main" yaml:"main"` // whether it is the main worktree of the repository
+}
+
+func NewCurrentCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Show the worktree containing the current directory",
+		Long: `Show the branch and path of the worktree containing the current directory. It works
+from any subdirectory and through symlinked paths, and fails outside a worktree.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			current, err := manager.CurrentWorktree()
+			if err != nil {
+				return err
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			record := currentRecord{Branch: current.Branch, Path: current.Path, HEAD: current.HEAD, Main: worktree.SamePath(current.Path, repoRoot)}
+			return output.Write(cmd.OutOrStdout(), format, record, func(w io.Writer) error {
+				branch := record.Branch
+				if branch == "" {
+					branch = "(detached)"
+				}
+				fmt.Fprintf(w, "%s\t%s\n", branch, record.Path)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
+	return cmd
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/worktree"
+)
+
+func NewDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with managed worktrees",
+		Long: `Diagnose problems with managed worktrees.
+
+--orphans lists directories under wkit_root that are no longer registered
+worktrees, and checkouts left where an absolute path_template places worktrees. Orphans that still hold a checkout can be re-registered with
+'wkit adopt <dir>'; --purge deletes the remaining ones and leaves checkouts alone,
+including checkouts of other repositories.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orphans, _ := cmd.Flags().GetBool("orphans")
+			purge, _ := cmd.Flags().GetBool("purge")
+			force, _ := cmd.Flags().GetBool("force")
+
+			// --purge acts on the orphans, so it implies --orphans
+			if !orphans && !purge {
+				return fmt.Errorf("select a check to run, e.g. --orphans")
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			wkitRoot := cfg.ResolveWkitRoot(repoRoot)
+			orphanedDirs, err := manager.FindOrphanedDirectories(wkitRoot)
+			if err != nil {
+				return fmt.Errorf("failed to find orphaned directories: %w", err)
+			}
+			roots := []string{wkitRoot}
+
+			// An absolute path_template can place worktrees outside wkit_root, in directories
+			// that may hold other projects, so only checkouts of this repository are listed there
+			if templateRoot := cfg.ResolvePathTemplateRoot(repoRoot); templateRoot != "" && !isWithinDir(wkitRoot, templateRoot) {
+				checkouts, err := manager.FindOrphanedCheckouts(templateRoot)
+				if err != nil {
+					return fmt.Errorf("failed to find orphaned checkouts: %w", err)
+				}
+				orphanedDirs = append(orphanedDirs, checkouts...)
+				roots = append(roots, templateRoot)
+			}
+
+			if len(orphanedDirs) == 0 {
+				fmt.Println("No orphaned directories found.")
+				return nil
+			}
+
+			fmt.Printf("Found %d orphaned director(ies) under '%s':\n", len(orphanedDirs), strings.Join(roots, "', '"))
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "PATH\tSIZE\tCHECKOUT")
+			fmt.Fprintln(w, "----\t----\t--------")
+			for _, od := range orphanedDirs {
+				checkout := "no"
+				if od.IsCheckout {
+					checkout = "yes"
+				} else if od.IsForeign {
+					checkout = "other repository"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", relativeToRoot(repoRoot, od.Path), formatSize(od.Size), checkout)
+			}
+			w.Flush()
+
+			if !purge {
+				fmt.Println("\nRun 'wkit adopt <dir>' to re-register a checkout, or 'wkit doctor --purge' to delete the rest.")
+				return nil
+			}
+
+			// Checkouts can be recovered with wkit adopt, and those of other repositories are
+			// not ours to delete, so only the rest goes
+			purgeable := purgeableOrphans(orphanedDirs)
+			if len(purgeable) < len(orphanedDirs) {
+				fmt.Printf("\nKeeping %d checkout(s); re-register those of this repository with 'wkit adopt <dir>' or delete them by hand.\n", len(orphanedDirs)-len(purgeable))
+			}
+			if len(purgeable) == 0 {
+				fmt.Println("Nothing to delete.")
+				return nil
+			}
+
+			if !force {
+				fmt.Println("\nThese directories will be deleted:")
+				for _, od := range purgeable {
+					fmt.Printf("  %s\n", relativeToRoot(repoRoot, od.Path))
+				}
+				fmt.Print("\nDelete these directories? (y/N): ")
+				var confirm string
+				fmt.Scanln(&confirm)
+				if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			for _, od := range purgeable {
+				if err := manager.PurgeOrphanedDirectory(od.Path); err != nil {
+					fmt.Fprintf(os.Stderr, "Error deleting %s: %v\n", od.Path, err)
+					continue
+				}
+				fmt.Printf("✓ Deleted '%s'\n", od.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("orphans", false, "List directories under wkit_root that are not registered worktrees")
+	cmd.Flags().Bool("purge", false, "Delete orphaned directories that are not checkouts")
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	return cmd
+}
+
+// purgeableOrphans returns the orphaned directories that are not checkouts of any repository
+func purgeableOrphans(orphanedDirs []worktree.OrphanedDirectory) []worktree.OrphanedDirectory {
+	var purgeable []worktree.OrphanedDirectory
+	for _, od := range orphanedDirs {
+		if !od.IsCheckout && !od.IsForeign {
+			purgeable = append(purgeable, od)
+		}
+	}
+	return purgeable
+}
+
+// relativeToRoot formats a path relative to the repository root for display
+func relativeToRoot(repoRoot string, path string) string {
+	if path == repoRoot {
+		return "(root)"
+	}
+	relativePath, err := filepath.Rel(repoRoot, path)
+	if err != nil {
+		return path
+	}
+	return relativePath
+}
+
+// formatSize formats a byte count in human-readable units
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
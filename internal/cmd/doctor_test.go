@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"wkit/internal/worktree"
+)
+
+func TestPurgeableOrphans(t *testing.T) {
+	orphanedDirs := []worktree.OrphanedDirectory{
+		{Path: "/repo/.git/.wkit-worktrees/stale", IsCheckout: false},
+		{Path: "/repo/.git/.wkit-worktrees/recoverable", IsCheckout: true},
+		{Path: "/repo/.git/.wkit-worktrees/empty", IsCheckout: false},
+		{Path: "/repo/.git/.wkit-worktrees/other-repo", IsForeign: true},
+	}
+
+	expected := []worktree.OrphanedDirectory{orphanedDirs[0], orphanedDirs[2]}
+	if got := purgeableOrphans(orphanedDirs); !reflect.DeepEqual(got, expected) {
+		t.Errorf("purgeableOrphans() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/worktree"
+)
+
+func NewExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec [--all | --filter <glob> | <worktree>...] -- <command> [args...]",
+		Short: "Run a command in several worktrees",
+		Long: `Run a command in each selected worktree, in parallel. Every output line is prefixed
+with the branch of the worktree it came from, and a pass/fail summary is printed at the
+end. wkit exits with an error when the command fails in any worktree. Without --all,
+--filter or worktree names, the command runs in the current worktree.
+
+--filter matches a glob against the branch, or the path relative to the repository root
+for detached worktrees, e.g. 'feature/*'. The command runs without a shell; use
+'sh -c' for pipes and redirections.
+
+  wkit exec --all -- go test ./...
+  wkit exec --filter 'feature/*' -- git pull
+  wkit exec api web -- sh -c 'make lint > lint.log'`,
+		ValidArgsFunction: completeWorktreeList,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			filter, _ := cmd.Flags().GetString("filter")
+			parallelism, _ := cmd.Flags().GetInt("parallel")
+
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return fmt.Errorf("a command is required after --, e.g. wkit exec --all -- go test ./...")
+			}
+			names, command := args[:dash], args[dash:]
+
+			selections := 0
+			for _, selected := range []bool{all, filter != "", len(names) > 0} {
+				if selected {
+					selections++
+				}
+			}
+			if selections > 1 {
+				return fmt.Errorf("select worktrees with only one of --all, --filter or worktree names")
+			}
+			if filter != "" {
+				if _, err := path.Match(filter, ""); err != nil {
+					return fmt.Errorf("invalid filter pattern %q: %w", filter, err)
+				}
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			var targets []worktree.Worktree
+			switch {
+			case len(names) > 0:
+				targets, err = resolveExecTargets(manager, names)
+			case !all && filter == "":
+				var current *worktree.Worktree
+				current, err = manager.CurrentWorktree()
+				if errors.Is(err, worktree.ErrNotInWorktree) {
+					err = fmt.Errorf("not inside a worktree; select worktrees with --all, --filter or worktree names")
+				} else if err == nil {
+					targets = []worktree.Worktree{*current}
+				}
+			default:
+				targets, err = manager.ListWorktrees()
+				if filter != "" {
+					targets = filterExecTargets(targets, filter, repoRoot)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if len(targets) == 0 && filter != "" {
+				return fmt.Errorf("no worktrees match '%s'", filter)
+			}
+			if len(targets) == 0 {
+				return fmt.Errorf("no worktrees found")
+			}
+
+			// From here on, errors are about the command, not how wkit was invoked
+			cmd.SilenceUsage = true
+
+			results := runInWorktrees(cmd.Context(), targets, command, repoRoot, parallelism, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			failed := writeExecSummary(cmd.OutOrStdout(), results)
+			if failed > 0 {
+				return fmt.Errorf("command failed in %d of %d worktrees", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Run in every worktree")
+	cmd.Flags().String("filter", "", "Run in worktrees whose branch matches a glob, e.g. 'feature/*'")
+	cmd.Flags().IntP("parallel", "j", 0, "Number of worktrees to run in concurrently (0 = one per CPU)")
+	return cmd
+}
+
+// execResult is the outcome of running the command in a worktree
+type execResult struct {
+	label    string
+	err      error
+	duration time.Duration
+}
+
+// resolveExecTargets finds the worktrees named on the command line, skipping duplicates.
+// Fuzzy matches are not accepted, so a typo never runs the command somewhere unexpected.
+func resolveExecTargets(manager *worktree.Manager, names []string) ([]worktree.Worktree, error) {
+	worktrees, err := manager.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var targets []worktree.Worktree
+	for _, name := range names {
+		worktreePath, err := manager.FindWorktreePathStrict(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find worktree path: %w", err)
+		}
+		if seen[worktreePath] {
+			continue
+		}
+		seen[worktreePath] = true
+		for _, wt := range worktrees {
+			if wt.Path == worktreePath {
+				targets = append(targets, wt)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// filterExecTargets keeps the worktrees whose label matches pattern
+func filterExecTargets(worktrees []worktree.Worktree, pattern string, repoRoot string) []worktree.Worktree {
+	var kept []worktree.Worktree
+	for _, wt := range worktrees {
+		if ok, _ := path.Match(pattern, execLabel(wt, repoRoot)); ok {
+			kept = append(kept, wt)
+		}
+	}
+	return kept
+}
+
+// execLabel names a worktree in output prefixes: its branch, or its relative path when detached
+func execLabel(wt worktree.Worktree, repoRoot string) string {
+	if wt.Branch != "" {
+		return wt.Branch
+	}
+	return relativeToRoot(repoRoot, wt.Path)
+}
+
+// runInWorktrees runs command in every target, at most parallelism at once (one per CPU when
+// it is 0 or less). Results keep the order of targets.
+func runInWorktrees(ctx context.Context, targets []worktree.Worktree, command []string, repoRoot string, parallelism int, stdout io.Writer, stderr io.Writer) []execResult {
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	width := 0
+	for _, wt := range targets {
+		width = max(width, len(execLabel(wt, repoRoot)))
+	}
+
+	// Lines from different worktrees may interleave, but each line is written whole
+	var mu sync.Mutex
+	results := make([]execResult, len(targets))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, wt := range targets {
+		label := execLabel(wt, repoRoot)
+		results[i].label = label
+		prefix := fmt.Sprintf("[%-*s] ", width, label)
+
+		wg.Add(1)
+		go func(i int, wt worktree.Worktree) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			out := &prefixWriter{w: stdout, prefix: prefix, mu: &mu}
+			errOut := &prefixWriter{w: stderr, prefix: prefix, mu: &mu}
+
+			c := exec.CommandContext(ctx, command[0], command[1:]...)
+			c.Dir = wt.Path
+			c.Stdout = out
+			c.Stderr = errOut
+
+			start := time.Now()
+			results[i].err = c.Run()
+			results[i].duration = time.Since(start)
+			out.Flush()
+			errOut.Flush()
+		}(i, wt)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// writeExecSummary prints whether the command passed in each worktree and returns the
+// number of failures
+func writeExecSummary(w io.Writer, results []execResult) int {
+	failed := 0
+	fmt.Fprintln(w)
+	for _, result := range results {
+		duration := result.duration.Round(100 * time.Millisecond)
+		if result.err != nil {
+			failed++
+			fmt.Fprintf(w, "✗ %s (%v, %s)\n", result.label, result.err, duration)
+		} else {
+			fmt.Fprintf(w, "✓ %s (%s)\n", result.label, duration)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", len(results)-failed, failed)
+	return failed
+}
+
+// prefixWriter writes each complete line to w with prefix, holding back a trailing partial
+// line until it is completed or flushed
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := p.buf.Next(i + 1)
+		p.writeLine(string(line))
+	}
+	return len(data), nil
+}
+
+// Flush writes a trailing partial line, ending it with a newline
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() > 0 {
+		p.writeLine(p.buf.String() + "\n")
+		p.buf.Reset()
+	}
+}
+
+func (p *prefixWriter) writeLine(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	io.WriteString(p.w, p.prefix+line)
+}
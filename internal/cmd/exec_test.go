@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"wkit/internal/worktree"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{w: &out, prefix: "[main] ", mu: &sync.Mutex{}}
+
+	w.Write([]byte("first line\nsecond "))
+	w.Write([]byte("line\nno newline"))
+	if out.String() != "[main] first line\n[main] second line\n" {
+		t.Errorf("Before Flush: %q", out.String())
+	}
+
+	w.Flush()
+	expected := "[main] first line\n[main] second line\n[main] no newline\n"
+	if out.String() != expected {
+		t.Errorf("After Flush: %q, want %q", out.String(), expected)
+	}
+}
+
+func TestFilterExecTargets(t *testing.T) {
+	worktrees := []worktree.Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/.git/.wkit-worktrees/feature/a", Branch: "feature/a"},
+		{Path: "/repo/.git/.wkit-worktrees/feature/b", Branch: "feature/b"},
+		{Path: "/repo/.git/.wkit-worktrees/detached"},
+	}
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"feature/*", []string{"feature/a", "feature/b"}},
+		{"main", []string{"main"}},
+		{".git/.wkit-worktrees/det*", []string{".git/.wkit-worktrees/detached"}},
+		{"release/*", nil},
+	}
+
+	for _, tt := range tests {
+		var labels []string
+		for _, wt := range filterExecTargets(worktrees, tt.pattern, "/repo") {
+			labels = append(labels, execLabel(wt, "/repo"))
+		}
+		if !reflect.DeepEqual(labels, tt.expected) {
+			t.Errorf("filterExecTargets(%q) = %v, want %v", tt.pattern, labels, tt.expected)
+		}
+	}
+}
+
+func TestRunInWorktrees(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("Skipping TestRunInWorktrees: sh not available")
+	}
+
+	targets := []worktree.Worktree{
+		{Path: t.TempDir(), Branch: "ok"},
+		{Path: t.TempDir(), Branch: "failing"},
+	}
+	command := []string{"sh", "-c", `echo "in $PWD"; if [ "$PWD" = "` + targets[1].Path + `" ]; then exit 3; fi`}
+
+	var stdout, stderr bytes.Buffer
+	results := runInWorktrees(context.Background(), targets, command, "/repo", 1, &stdout, &stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	sort.Strings(lines)
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[failing] in ") && !strings.HasPrefix(line, "[ok     ] in ") {
+			t.Errorf("Unexpected output line: %q", line)
+		}
+	}
+	if len(lines) != 2 {
+		t.Errorf("Expected 2 output lines, got %q", stdout.String())
+	}
+
+	if results[0].label != "ok" || results[0].err != nil {
+		t.Errorf("results[0] = %+v, want ok to pass", results[0])
+	}
+	var exitErr *exec.ExitError
+	if results[1].label != "failing" || !errors.As(results[1].err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("results[1] = %+v, want failing to exit with 3", results[1])
+	}
+
+	var summary bytes.Buffer
+	if failed := writeExecSummary(&summary, results); failed != 1 {
+		t.Errorf("writeExecSummary() = %d failures, want 1", failed)
+	}
+	if !strings.Contains(summary.String(), "1 passed, 1 failed") {
+		t.Errorf("Summary does not count results: %s", summary.String())
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/index"
+	"wkit/internal/output"
+	"wkit/internal/worktree"
+)
+
+func NewJumpCmd() *cobra.Command {
+	var list bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "jump <query>...",
+		Short: "Jump to a worktree of any repository by frecency",
+		Long: `Print the path of the best matching worktree across every repository used with wkit,
+for shell wrappers. Worktrees are matched by "<repository>/<branch>" and ranked by
+frecency, like zoxide: how often and how recently they were visited with add or switch.
+With several keywords, every keyword must match.
+
+The index is kept in $XDG_DATA_HOME/wkit/index.json (~/.local/share/wkit by default).`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				if err := output.Validate(format); err != nil {
+					return err
+				}
+			} else if format != "" {
+				return fmt.Errorf("--format can only be used with --list")
+			}
+
+			indexPath, err := index.DefaultPath()
+			if err != nil {
+				return err
+			}
+			var matches []index.Match
+			err = index.Update(indexPath, func(idx *index.Index) error {
+				now := time.Now()
+				for _, match := range idx.Query(args, now) {
+					if !pathExists(match.Path) {
+						// Worktrees removed without wkit are dropped lazily
+						idx.Remove(match.Path)
+						continue
+					}
+					matches = append(matches, match)
+				}
+				if !list && len(matches) > 0 {
+					idx.Visit(matches[0].Path, matches[0].Repo, matches[0].Branch, now)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if list {
+				return writeJumpMatches(cmd.OutOrStdout(), format, matches)
+			}
+			if len(matches) == 0 {
+				return fmt.Errorf("no indexed worktree matches '%s'", strings.Join(args, " "))
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), matches[0].Path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List every match with its score instead of printing the best one")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage+" (with --list)")
+	return cmd
+}
+
+func writeJumpMatches(out io.Writer, format string, matches []index.Match) error {
+	if matches == nil {
+		matches = []index.Match{}
+	}
+	return output.Write(out, format, matches, func(w io.Writer) error {
+		if len(matches) == 0 {
+			fmt.Fprintln(w, "No matching worktrees.")
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SCORE\tWORKTREE\tPATH")
+		for _, match := range matches {
+			fmt.Fprintf(tw, "%.1f\t%s\t%s\n", match.Score, match.Label(), match.Path)
+		}
+		return tw.Flush()
+	})
+}
+
+// visitIndex records a visit to a worktree of the current repository in the global jump index
+func visitIndex(manager *worktree.Manager, worktreePath string) {
+	err := updateIndex(func(idx *index.Index) error {
+		repoRoot, err := worktree.GetRepositoryRoot()
+		if err != nil {
+			return err
+		}
+		worktrees, err := manager.ListWorktrees()
+		if err != nil {
+			return err
+		}
+
+		branch := ""
+		for _, wt := range worktrees {
+			if wt.Path == worktreePath {
+				branch = wt.Branch
+			}
+		}
+		idx.Visit(worktreePath, repoRoot, branch, time.Now())
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to update the jump index: %v\n", err)
+	}
+}
+
+// forgetIndex drops a removed worktree from the global jump index
+func forgetIndex(worktreePath string) {
+	err := updateIndex(func(idx *index.Index) error {
+		idx.Remove(worktreePath)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to update the jump index: %v\n", err)
+	}
+}
+
+func updateIndex(update func(idx *index.Index) error) error {
+	indexPath, err := index.DefaultPath()
+	if err != nil {
+		return err
+	}
+	return index.Update(indexPath, update)
+}
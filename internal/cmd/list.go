@@ -1,23 +1,128 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
+// listWorktree is the machine-readable form of a worktree in the list
+type listWorktree struct {
+	Path   string `json:"path" yaml:"path"`
+	Branch string `json:"branch" yaml:"branch"`
+	HEAD   string `json:"head" yaml:"head"`
+}
+
+// longListWorktree adds the details shown by list --long
+type longListWorktree struct {
+	listWorktree `yaml:",inline"`
+	MainRef      string    `json:"main_ref,omitempty" yaml:"main_ref,omitempty"`
+	MainAhead    int       `json:"main_ahead" yaml:"main_ahead"`
+	MainBehind   int       `json:"main_behind" yaml:"main_behind"`
+	Upstream     string    `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Dirty        bool      `json:"dirty" yaml:"dirty"`
+	Locked       bool      `json:"locked" yaml:"locked"`
+	Subject      string    `json:"subject" yaml:"subject"`
+	Author       string    `json:"author" yaml:"author"`
+	Committed    time.Time `json:"committed" yaml:"committed"`
+	Size         int64     `json:"size,omitempty" yaml:"size,omitempty"` // only computed when the size column or sort is used
+}
+
+// listColumn is a column that list can show, filter on and sort by
+type listColumn struct {
+	header string
+	value  func(record longListWorktree, now time.Time) string
+}
+
+var listColumns = map[string]listColumn{
+	"path":   {header: "PATH", value: func(r longListWorktree, _ time.Time) string { return r.Path }},
+	"branch": {header: "BRANCH", value: func(r longListWorktree, _ time.Time) string { return r.Branch }},
+	"head": {header: "HEAD", value: func(r longListWorktree, _ time.Time) string {
+		// Truncate HEAD to 7 characters for display
+		if len(r.HEAD) > 7 {
+			return r.HEAD[:7]
+		}
+		return r.HEAD
+	}},
+	"main": {header: "MAIN", value: func(r longListWorktree, _ time.Time) string {
+		return formatMainDivergence(r.MainRef, r.MainAhead, r.MainBehind)
+	}},
+	"upstream": {header: "UPSTREAM", value: func(r longListWorktree, _ time.Time) string { return r.Upstream }},
+	"dirty":    {header: "DIRTY", value: func(r longListWorktree, _ time.Time) string { return yesNo(r.Dirty) }},
+	"locked":   {header: "LOCKED", value: func(r longListWorktree, _ time.Time) string { return yesNo(r.Locked) }},
+	"age": {header: "AGE", value: func(r longListWorktree, now time.Time) string {
+		if r.Committed.IsZero() {
+			return ""
+		}
+		return formatAge(r.Committed, now)
+	}},
+	"author":  {header: "AUTHOR", value: func(r longListWorktree, _ time.Time) string { return r.Author }},
+	"subject": {header: "SUBJECT", value: func(r longListWorktree, _ time.Time) string { return r.Subject }},
+	"size":    {header: "SIZE", value: func(r longListWorktree, _ time.Time) string { return formatSize(r.Size) }},
+}
+
+// listColumnNames is the order columns are documented in
+var listColumnNames = []string{"path", "head", "branch", "main", "upstream", "dirty", "locked", "age", "author", "subject", "size"}
+
+var (
+	defaultListColumns = []string{"path", "head", "branch"}
+	longListColumns    = []string{"path", "head", "branch", "main", "upstream", "dirty", "locked", "age", "author", "subject"}
+)
+
 func NewListCmd() *cobra.Command {
 	var format string
+	var long bool
+	var columnsFlag string
+	var sortBy string
+	var filters []string
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all worktrees",
-		Long:  `List all Git worktrees associated with the current repository.`,
+		Long: `List all Git worktrees associated with the current repository.
+
+--long adds the divergence from the remote main branch, upstream, dirty and locked
+flags, and the age, author and subject of the last commit. --columns picks columns
+from: ` + strings.Join(listColumnNames, ", ") + `.
+--filter key=glob keeps worktrees whose column value matches the glob, e.g. 'branch=feature/*'.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
+			columns := defaultListColumns
+			if long {
+				columns = longListColumns
+			}
+			if columnsFlag != "" {
+				columns = strings.Split(columnsFlag, ",")
+			}
+			for _, name := range columns {
+				if _, ok := listColumns[name]; !ok {
+					return fmt.Errorf("unknown column: %s. Valid values: %s", name, strings.Join(listColumnNames, ", "))
+				}
+			}
+			switch sortBy {
+			case "", "age", "name", "size":
+			default:
+				return fmt.Errorf("invalid --sort value: %s. Valid values: age, name, size", sortBy)
+			}
+			parsedFilters, err := parseListFilters(filters)
+			if err != nil {
+				return err
+			}
+
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
@@ -34,13 +139,7 @@ func NewListCmd() *cobra.Command {
 			}
 
 			// Convert absolute paths to relative paths for output
-			type outputWorktree struct {
-				Path   string `json:"path"`
-				Branch string `json:"branch"`
-				HEAD   string `json:"head"`
-			}
-
-			outputWorktrees := make([]outputWorktree, 0, len(worktrees))
+			outputWorktrees := make([]listWorktree, 0, len(worktrees))
 			for _, wt := range worktrees {
 				var relativePath string
 				if wt.Path == repoRoot {
@@ -52,42 +151,176 @@ func NewListCmd() *cobra.Command {
 						relativePath = wt.Path // Fallback if relative path calculation fails
 					}
 				}
-				outputWorktrees = append(outputWorktrees, outputWorktree{
+				outputWorktrees = append(outputWorktrees, listWorktree{
 					Path:   relativePath,
 					Branch: wt.Branch,
 					HEAD:   wt.HEAD,
 				})
 			}
 
-			// Output based on format
-			if format == "json" {
-				encoder := json.NewEncoder(cmd.OutOrStdout())
-				encoder.SetIndent("", "  ")
-				return encoder.Encode(outputWorktrees)
+			if !long && columnsFlag == "" && sortBy == "" && len(parsedFilters) == 0 {
+				return output.Write(cmd.OutOrStdout(), format, outputWorktrees, func(out io.Writer) error {
+					return writeListTable(out, columns, toLongListWorktrees(outputWorktrees), time.Now())
+				})
 			}
 
-			// Default human-readable format using tabwriter for proper alignment
-			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
-			defer w.Flush()
+			withSize := sortBy == "size"
+			for _, name := range columns {
+				withSize = withSize || name == "size"
+			}
+			records, err := collectLongList(cmd, manager, worktrees, outputWorktrees, withSize)
+			if err != nil {
+				return err
+			}
 
-			// Header
-			fmt.Fprintln(w, "PATH\tHEAD\tBRANCH")
-			fmt.Fprintln(w, "----\t----\t------")
+			now := time.Now()
+			records = filterLongList(records, parsedFilters, now)
+			sortLongList(records, sortBy)
 
-			for _, wt := range outputWorktrees {
-				// Truncate HEAD to 7 characters for display
-				displayHEAD := wt.HEAD
-				if len(displayHEAD) > 7 {
-					displayHEAD = displayHEAD[:7]
-				}
-				// Format with tabs for proper alignment
-				fmt.Fprintf(w, "%s\t%s\t%s\n", wt.Path, displayHEAD, wt.Branch)
-			}
-			return nil
+			return output.Write(cmd.OutOrStdout(), format, records, func(out io.Writer) error {
+				return writeListTable(out, columns, records, now)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "", "Output format (json)")
+	cmd.Flags().BoolVarP(&long, "long", "l", false, "Show details such as divergence from the remote main branch and the last commit")
+	cmd.Flags().StringVar(&columnsFlag, "columns", "", "Comma-separated columns to show: "+strings.Join(listColumnNames, ","))
+	cmd.Flags().StringVar(&sortBy, "sort", "", "Sort by age (newest first), name or size (largest first)")
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "Only show worktrees whose column matches a glob, e.g. 'branch=feature/*' (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
 
 	return cmd
 }
+
+// collectLongList gathers the details of each worktree; status runs concurrently like in wkit status
+func collectLongList(cmd *cobra.Command, manager *worktree.Manager, worktrees []worktree.Worktree, outputWorktrees []listWorktree, withSize bool) ([]longListWorktree, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	results := manager.CollectStatuses(cmd.Context(), worktrees, cfg.MainBranch, cfg.Status.Parallelism, cfg.Status.Timeout)
+
+	records := toLongListWorktrees(outputWorktrees)
+	for i, result := range results {
+		record := &records[i]
+		record.Locked = result.Worktree.Locked
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to get status for %s: %v\n", record.Path, result.Err)
+		} else {
+			record.MainRef = result.Status.MainRef
+			record.MainAhead = result.Status.MainAhead
+			record.MainBehind = result.Status.MainBehind
+			record.Upstream = result.Status.Upstream
+			record.Dirty = !result.Status.IsClean
+		}
+
+		if info, err := manager.GetCommitInfo(result.Worktree.Path); err == nil {
+			record.Subject = info.Subject
+			record.Author = info.Author
+			record.Committed = info.Committed
+		}
+
+		if withSize {
+			record.Size, err = worktree.WorktreeSize(result.Worktree.Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to get size of %s: %v\n", record.Path, err)
+			}
+		}
+	}
+	return records, nil
+}
+
+func toLongListWorktrees(outputWorktrees []listWorktree) []longListWorktree {
+	records := make([]longListWorktree, 0, len(outputWorktrees))
+	for _, wt := range outputWorktrees {
+		records = append(records, longListWorktree{listWorktree: wt})
+	}
+	return records
+}
+
+// writeListTable writes the selected columns using tabwriter for proper alignment
+func writeListTable(out io.Writer, columns []string, records []longListWorktree, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	headers := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, name := range columns {
+		headers[i] = listColumns[name].header
+		separators[i] = strings.Repeat("-", len(headers[i]))
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	fmt.Fprintln(w, strings.Join(separators, "\t"))
+
+	for _, record := range records {
+		values := make([]string, len(columns))
+		for i, name := range columns {
+			values[i] = listColumns[name].value(record, now)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return nil
+}
+
+// listFilter keeps worktrees whose column value matches pattern
+type listFilter struct {
+	column  string
+	pattern string
+}
+
+// parseListFilters parses --filter values of the form key=glob
+func parseListFilters(filters []string) ([]listFilter, error) {
+	var parsed []listFilter
+	for _, filter := range filters {
+		column, pattern, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter: %s. Use key=glob, e.g. 'branch=feature/*'", filter)
+		}
+		if _, ok := listColumns[column]; !ok {
+			return nil, fmt.Errorf("unknown filter key: %s. Valid values: %s", column, strings.Join(listColumnNames, ", "))
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+		}
+		parsed = append(parsed, listFilter{column: column, pattern: pattern})
+	}
+	return parsed, nil
+}
+
+// filterLongList keeps the records that match every filter
+func filterLongList(records []longListWorktree, filters []listFilter, now time.Time) []longListWorktree {
+	var kept []longListWorktree
+	for _, record := range records {
+		matched := true
+		for _, filter := range filters {
+			if ok, _ := path.Match(filter.pattern, listColumns[filter.column].value(record, now)); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// sortLongList sorts by age (newest first), name or size (largest first), keeping git's order otherwise
+func sortLongList(records []longListWorktree, sortBy string) {
+	switch sortBy {
+	case "age":
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Committed.After(records[j].Committed) })
+	case "name":
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Branch < records[j].Branch })
+	case "size":
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Size > records[j].Size })
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestListCommand(t *testing.T) {
@@ -161,3 +163,59 @@ func TestListCommandJSONFormat(t *testing.T) {
 	// We would need to mock the worktree manager here
 	// For now, this is a placeholder to show the test structure
 }
+
+func TestParseListFilters(t *testing.T) {
+	filters, err := parseListFilters([]string{"branch=feature/*", "dirty=yes"})
+	if err != nil {
+		t.Fatalf("parseListFilters() failed: %v", err)
+	}
+	if len(filters) != 2 || filters[0] != (listFilter{column: "branch", pattern: "feature/*"}) {
+		t.Errorf("parseListFilters() = %+v", filters)
+	}
+
+	for _, invalid := range []string{"branch", "color=red", "branch=[x"} {
+		if _, err := parseListFilters([]string{invalid}); err == nil {
+			t.Errorf("parseListFilters(%q) expected error, got nil", invalid)
+		}
+	}
+}
+
+func TestFilterAndSortLongList(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []longListWorktree{
+		{listWorktree: listWorktree{Branch: "main"}, Committed: now.Add(-time.Hour), Size: 300},
+		{listWorktree: listWorktree{Branch: "feature/b"}, Committed: now.Add(-2 * time.Hour), Size: 100, Dirty: true},
+		{listWorktree: listWorktree{Branch: "feature/a"}, Committed: now.Add(-time.Minute), Size: 200},
+		{listWorktree: listWorktree{Branch: "feature/a/nested"}, Committed: now.Add(-3 * time.Hour)},
+	}
+
+	branches := func(records []longListWorktree) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Branch)
+		}
+		return result
+	}
+	assertBranches := func(name string, got []longListWorktree, expected ...string) {
+		t.Helper()
+		if result := branches(got); fmt.Sprint(result) != fmt.Sprint(expected) {
+			t.Errorf("%s = %v, want %v", name, result, expected)
+		}
+	}
+
+	filtered := filterLongList(records, []listFilter{{column: "branch", pattern: "feature/*"}}, now)
+	assertBranches("filter branch=feature/*", filtered, "feature/b", "feature/a")
+
+	filtered = filterLongList(records, []listFilter{{column: "branch", pattern: "feature/*"}, {column: "dirty", pattern: "no"}}, now)
+	assertBranches("filter branch=feature/* dirty=no", filtered, "feature/a")
+
+	sorted := append([]longListWorktree(nil), records...)
+	sortLongList(sorted, "age")
+	assertBranches("sort age", sorted, "feature/a", "main", "feature/b", "feature/a/nested")
+
+	sortLongList(sorted, "name")
+	assertBranches("sort name", sorted, "feature/a", "feature/a/nested", "feature/b", "main")
+
+	sortLongList(sorted, "size")
+	assertBranches("sort size", sorted, "main", "feature/a", "feature/b", "feature/a/nested")
+}
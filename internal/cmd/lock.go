@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/worktree"
+)
+
+func NewLockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "lock [worktree]",
+		Short:             "Lock a worktree so that it is not pruned, moved or removed",
+		Long:              `Lock a worktree so that it is not pruned, moved or removed. Without an argument, lock the current worktree, or pick one interactively when not inside a worktree.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeWorktrees,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reason, _ := cmd.Flags().GetString("reason")
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			worktreePath, err := resolveWorktreeArgOrCurrent(manager, args)
+			if err != nil {
+				return err
+			}
+
+			err = manager.LockWorktree(worktreePath, reason)
+			if err != nil {
+				return fmt.Errorf("failed to lock worktree: %w", err)
+			}
+
+			fmt.Printf("✓ Locked worktree '%s'\n", worktreePath)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("reason", "", "Reason for locking the worktree")
+	return cmd
+}
+
+func NewUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unlock [worktree]",
+		Short:             "Unlock a locked worktree",
+		Long:              `Unlock a locked worktree. Without an argument, unlock the current worktree, or pick one interactively when not inside a worktree.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeWorktrees,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			worktreePath, err := resolveWorktreeArgOrCurrent(manager, args)
+			if err != nil {
+				return err
+			}
+
+			err = manager.UnlockWorktree(worktreePath)
+			if err != nil {
+				return fmt.Errorf("failed to unlock worktree: %w", err)
+			}
+
+			fmt.Printf("✓ Unlocked worktree '%s'\n", worktreePath)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/worktree"
+)
+
+// relocation describes where a worktree will be moved, or why it is skipped
+type relocation struct {
+	Worktree worktree.Worktree
+	Target   string
+	Skip     string
+}
+
+func NewRelocateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "relocate [worktree...]",
+		ValidArgsFunction: completeWorktreeList,
+		Short:             "Move worktrees outside wkit_root into the managed layout",
+		Long: `Move worktrees that live outside wkit_root to their canonical location.
+
+When path_template is set, every worktree that is not at its templated
+location is relocated.
+
+Dirty, locked and detached worktrees are skipped.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			force, _ := cmd.Flags().GetBool("force")
+
+			if !all && len(args) == 0 {
+				return fmt.Errorf("specify worktrees to relocate or use --all")
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			worktrees, err := manager.ListWorktrees()
+			if err != nil {
+				return fmt.Errorf("failed to list worktrees: %w", err)
+			}
+
+			var candidates []worktree.Worktree
+			if all {
+				// The first entry is the main worktree, which git cannot move
+				if len(worktrees) > 1 {
+					candidates = worktrees[1:]
+				}
+			} else {
+				for _, name := range args {
+					path, err := manager.FindWorktreePathStrict(name)
+					if err != nil {
+						return fmt.Errorf("failed to find worktree path: %w", err)
+					}
+					for _, wt := range worktrees {
+						if wt.Path == path {
+							candidates = append(candidates, wt)
+						}
+					}
+				}
+			}
+
+			wkitRoot := cfg.ResolveWkitRoot(repoRoot)
+			var relocations []relocation
+			for _, wt := range candidates {
+				if wt.Path == repoRoot {
+					continue
+				}
+				// Without a path_template every worktree under wkit_root is already managed
+				if all && cfg.PathTemplate == "" && isWithinDir(wkitRoot, wt.Path) {
+					continue
+				}
+
+				r := relocation{Worktree: wt}
+				if wt.Branch != "" {
+					r.Target, err = cfg.ResolveWkitPath(wt.Branch, "", repoRoot)
+					if err != nil {
+						return fmt.Errorf("failed to resolve worktree path: %w", err)
+					}
+				}
+				if all && wt.Path == r.Target {
+					continue
+				}
+
+				switch {
+				case wt.Branch == "":
+					r.Skip = "detached HEAD"
+				case wt.Path == r.Target:
+					r.Skip = "already in place"
+				case wt.Locked:
+					r.Skip = "locked"
+				case pathExists(r.Target):
+					r.Skip = "target exists"
+				default:
+					status, err := manager.GetWorktreeStatus(wt.Path)
+					if err != nil {
+						r.Skip = "status unavailable"
+					} else if !status.IsClean {
+						r.Skip = "dirty"
+					}
+				}
+				relocations = append(relocations, r)
+			}
+
+			if len(relocations) == 0 {
+				fmt.Println("No worktrees to relocate.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "BRANCH\tFROM\tTO\tACTION")
+			fmt.Fprintln(w, "------\t----\t--\t------")
+			movable := 0
+			for _, r := range relocations {
+				action := "move"
+				if r.Skip != "" {
+					action = "skip (" + r.Skip + ")"
+				} else {
+					movable++
+				}
+				target := "-"
+				if r.Target != "" {
+					target = relativeToRoot(repoRoot, r.Target)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Worktree.Branch, r.Worktree.Path, target, action)
+			}
+			w.Flush()
+
+			if dryRun || movable == 0 {
+				return nil
+			}
+
+			if !force {
+				fmt.Printf("\nMove %d worktree(s)? (y/N): ", movable)
+				var confirm string
+				fmt.Scanln(&confirm)
+				if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			for _, r := range relocations {
+				if r.Skip != "" {
+					continue
+				}
+				if err := manager.MoveWorktree(r.Worktree.Path, r.Target); err != nil {
+					fmt.Fprintf(os.Stderr, "Error moving worktree %s: %v\n", r.Worktree.Path, err)
+					continue
+				}
+				forgetIndex(r.Worktree.Path)
+				visitIndex(manager, r.Target)
+				fmt.Printf("✓ Moved '%s' to '%s'\n", r.Worktree.Path, r.Target)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Relocate every worktree outside wkit_root")
+	cmd.Flags().BoolP("dry-run", "n", false, "Show the preview without moving anything")
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	return cmd
+}
+
+// isWithinDir reports whether path is dir itself or located below it
+func isWithinDir(dir string, path string) bool {
+	relativePath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import "testing"
+
+func TestIsWithinDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "same directory",
+			dir:      "/repo/.git/.wkit-worktrees",
+			path:     "/repo/.git/.wkit-worktrees",
+			expected: true,
+		},
+		{
+			name:     "nested worktree",
+			dir:      "/repo/.git/.wkit-worktrees",
+			path:     "/repo/.git/.wkit-worktrees/feature/foo",
+			expected: true,
+		},
+		{
+			name:     "sibling directory",
+			dir:      "/repo/.git/.wkit-worktrees",
+			path:     "/repo/../proj-fix",
+			expected: false,
+		},
+		{
+			name:     "directory sharing a prefix",
+			dir:      "/repo/.git/.wkit-worktrees",
+			path:     "/repo/.git/.wkit-worktrees-old/fix",
+			expected: false,
+		},
+		{
+			name:     "dotted name inside",
+			dir:      "/work",
+			path:     "/work/..hidden",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isWithinDir(tt.dir, tt.path); result != tt.expected {
+				t.Errorf("isWithinDir(%q, %q) = %v, want %v", tt.dir, tt.path, result, tt.expected)
+			}
+		})
+	}
+}
@@ -9,28 +9,29 @@ import (
 
 func NewRemoveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "remove <worktree>",
-		Short: "Remove a worktree",
-		Args:  cobra.ExactArgs(1),
+		Use:               "remove [worktree]",
+		Short:             "Remove a worktree",
+		Long:              `Remove a worktree. Without an argument, pick the worktree interactively.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeWorktrees,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			worktreeName := args[0]
-
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
 			}
 
-			worktreePath, err := manager.FindWorktreePath(worktreeName)
+			worktreePath, err := resolveWorktreeArgStrict(manager, args)
 			if err != nil {
-				return fmt.Errorf("failed to find worktree path: %w", err)
+				return err
 			}
 
 			err = manager.RemoveWorktree(worktreePath)
 			if err != nil {
 				return fmt.Errorf("failed to remove worktree: %w", err)
 			}
+			forgetIndex(worktreePath)
 
-			fmt.Printf("✓ Removed worktree '%s'\n", worktreeName)
+			fmt.Printf("✓ Removed worktree '%s'\n", worktreePath)
 			return nil
 		},
 	}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"wkit/internal/tui"
+	"wkit/internal/worktree"
+)
+
+// resolveWorktreeArg finds the worktree named by args[0], or lets the user pick one
+// when no argument is given and wkit runs in a terminal
+func resolveWorktreeArg(manager *worktree.Manager, args []string) (string, error) {
+	return resolveWorktreeArgWith(manager, args, manager.FindWorktreePath)
+}
+
+// resolveWorktreeArgStrict is resolveWorktreeArg for destructive commands: the argument
+// must be the exact path, branch or directory name of a worktree, not a prefix or fuzzy match
+func resolveWorktreeArgStrict(manager *worktree.Manager, args []string) (string, error) {
+	return resolveWorktreeArgWith(manager, args, manager.FindWorktreePathStrict)
+}
+
+// resolveWorktreeArgOrCurrent is resolveWorktreeArg for commands that act on the current
+// worktree by default: without an argument, the picker is only shown outside a worktree
+func resolveWorktreeArgOrCurrent(manager *worktree.Manager, args []string) (string, error) {
+	return resolveWorktreeArgOrCurrentWith(manager, args, manager.FindWorktreePath)
+}
+
+// resolveWorktreeArgOrCurrentStrict is resolveWorktreeArgOrCurrent without fuzzy matching
+func resolveWorktreeArgOrCurrentStrict(manager *worktree.Manager, args []string) (string, error) {
+	return resolveWorktreeArgOrCurrentWith(manager, args, manager.FindWorktreePathStrict)
+}
+
+func resolveWorktreeArgOrCurrentWith(manager *worktree.Manager, args []string, find func(string) (string, error)) (string, error) {
+	if len(args) > 0 {
+		return resolveWorktreeArgWith(manager, args, find)
+	}
+
+	current, err := manager.CurrentWorktree()
+	switch {
+	case err == nil:
+		return current.Path, nil
+	case errors.Is(err, worktree.ErrNotInWorktree) && tui.CanPick():
+		// Outside a worktree, let the user pick one instead
+		return pickWorktree(manager)
+	default:
+		return "", err
+	}
+}
+
+func resolveWorktreeArgWith(manager *worktree.Manager, args []string, find func(string) (string, error)) (string, error) {
+	if len(args) > 0 {
+		worktreePath, err := find(args[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to find worktree path: %w", err)
+		}
+		return worktreePath, nil
+	}
+
+	if !tui.CanPick() {
+		return "", fmt.Errorf("a worktree argument is required when not running in a terminal")
+	}
+	return pickWorktree(manager)
+}
+
+// pickWorktree shows the fuzzy picker over all worktrees, previewing branch, path and status
+func pickWorktree(manager *worktree.Manager) (string, error) {
+	worktrees, err := manager.ListWorktrees()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	repoRoot, err := worktree.GetRepositoryRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	items := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		items[i] = fmt.Sprintf("%s  %s", branch, relativeToRoot(repoRoot, wt.Path))
+	}
+
+	picker := &tui.Picker{
+		Prompt: "worktree> ",
+		Items:  items,
+		Preview: func(index int) string {
+			return previewWorktree(manager, worktrees[index])
+		},
+	}
+	index, err := picker.Pick()
+	if errors.Is(err, tui.ErrCancelled) {
+		return "", fmt.Errorf("no worktree selected")
+	}
+	if err != nil {
+		return "", err
+	}
+	return worktrees[index].Path, nil
+}
+
+// previewWorktree describes a worktree for the picker preview
+func previewWorktree(manager *worktree.Manager, wt worktree.Worktree) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Branch: %s\n", wt.Branch)
+	fmt.Fprintf(&b, "Path:   %s\n", wt.Path)
+	fmt.Fprintf(&b, "HEAD:   %s\n", wt.HEAD)
+	if wt.Locked {
+		fmt.Fprintln(&b, "Locked: yes")
+	}
+
+	if info, err := manager.GetCommitInfo(wt.Path); err == nil {
+		fmt.Fprintf(&b, "Commit: %s (%s, %s)\n", info.Subject, info.Author, info.Committed.Format("2006-01-02"))
+	}
+	if status, err := manager.GetWorktreeStatus(wt.Path); err != nil {
+		fmt.Fprintf(&b, "Status: error: %v\n", err)
+	} else {
+		fmt.Fprintf(&b, "Status: %s\n", formatStatusSummary(status))
+	}
+	return b.String()
+}
@@ -1,10 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
@@ -21,21 +22,19 @@ func NewRootCmd() *cobra.Command {
 				return fmt.Errorf("failed to get repository root: %w", err)
 			}
 
-			// Output based on format
-			if format == "json" {
-				output := map[string]string{"root": repoRoot}
-				encoder := json.NewEncoder(cmd.OutOrStdout())
-				encoder.SetIndent("", "  ")
-				return encoder.Encode(output)
-			}
+			data := struct {
+				Root string `json:"root" yaml:"root"`
+			}{Root: repoRoot}
 
-			// Default human-readable format
-			fmt.Fprintln(cmd.OutOrStdout(), repoRoot)
-			return nil
+			return output.Write(cmd.OutOrStdout(), format, data, func(w io.Writer) error {
+				// Default human-readable format
+				fmt.Fprintln(w, repoRoot)
+				return nil
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "", "Output format (json)")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
 
 	return cmd
 }
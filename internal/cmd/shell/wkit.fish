@@ -0,0 +1,48 @@
+# wkit shell integration for fish.
+# Load it from ~/.config/fish/config.fish:
+#
+#   wkit shell-init fish | source
+#
+# The wkit function below changes directory after `wkit switch`, `wkit add` and `wkit jump`,
+# goes back to the repository root after removing the current worktree, and
+# supports `wkit switch -` to return to the previous worktree from wkit's history.
+
+function __wkit_cd
+    # $argv[1] is the directory printed by wkit, already mapped to the current subdirectory
+    builtin cd -- $argv[1]
+end
+
+function wkit --description 'Git worktree toolkit that changes directory on switch'
+    switch "$argv[1]"
+        case switch add jump
+            for arg in $argv
+                switch $arg
+                    case --no-switch --recent -l --list --format '--format=*' -h --help
+                        command wkit $argv
+                        return
+                end
+            end
+
+            set -l output (command wkit $argv)
+            set -l exit_status $status
+            if test $exit_status -ne 0
+                test (count $output) -gt 0; and printf '%s\n' $output
+                return $exit_status
+            end
+            test (count $output) -eq 0; and return
+
+            # The last line is the target; anything before it is progress output
+            if test (count $output) -gt 1
+                printf '%s\n' $output[1..-2]
+            end
+            __wkit_cd $output[-1]
+        case remove
+            set -l root (command wkit root 2>/dev/null)
+            command wkit $argv; or return
+            if not test -d "$PWD"; and test -n "$root"
+                builtin cd -- $root
+            end
+        case '*'
+            command wkit $argv
+    end
+end
@@ -0,0 +1,56 @@
+# wkit shell integration for bash and zsh.
+# Load it from ~/.bashrc or ~/.zshrc:
+#
+#   eval "$(wkit shell-init bash)"   # or zsh
+#
+# The wkit function below changes directory after `wkit switch`, `wkit add` and `wkit jump`,
+# goes back to the repository root after removing the current worktree, and
+# supports `wkit switch -` to return to the previous worktree from wkit's history.
+
+__wkit_cd() {
+    # $1 is the directory printed by wkit, already mapped to the current subdirectory
+    builtin cd -- "$1"
+}
+
+wkit() {
+    case "$1" in
+        switch|add|jump)
+            local arg
+            for arg in "$@"; do
+                case "$arg" in
+                    --no-switch|--recent|-l|--list|--format|--format=*|-h|--help)
+                        command wkit "$@"
+                        return
+                        ;;
+                esac
+            done
+
+            local output exit_status
+            output="$(command wkit "$@")"
+            exit_status=$?
+            if [ $exit_status -ne 0 ]; then
+                [ -n "$output" ] && printf '%s\n' "$output"
+                return $exit_status
+            fi
+            [ -z "$output" ] && return
+
+            # The last line is the target; anything before it is progress output
+            local target="${output##*$'\n'}"
+            if [ "$target" != "$output" ]; then
+                printf '%s\n' "${output%$'\n'*}"
+            fi
+            __wkit_cd "$target"
+            ;;
+        remove)
+            local root
+            root="$(command wkit root 2>/dev/null)"
+            command wkit "$@" || return
+            if [ ! -d "$PWD" ] && [ -n "$root" ]; then
+                builtin cd -- "$root"
+            fi
+            ;;
+        *)
+            command wkit "$@"
+            ;;
+    esac
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed shell/wkit.sh
+	posixShellInit string
+
+	//go:embed shell/wkit.fish
+	fishShellInit string
+)
+
+func NewShellInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell-init <bash|zsh|fish>",
+		Short: "Print a shell function that changes directory on switch, add and jump",
+		Long: `Print a wkit shell function for bash, zsh or fish. It changes directory after
+'wkit switch', 'wkit add' and 'wkit jump', goes back to the repository root after removing
+the current worktree, and changes to the previous worktree on 'wkit switch -'.
+The completion script from 'wkit completion' is included.
+
+  bash: eval "$(wkit shell-init bash)"   # in ~/.bashrc
+  zsh:  eval "$(wkit shell-init zsh)"    # in ~/.zshrc
+  fish: wkit shell-init fish | source    # in ~/.config/fish/config.fish`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			script, err := shellInitScript(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), script)
+			return writeCompletion(cmd.Root(), cmd.OutOrStdout(), args[0])
+		},
+	}
+}
+
+// shellInitScript returns the integration script for shell
+func shellInitScript(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return posixShellInit, nil
+	case "fish":
+		return fishShellInit, nil
+	}
+	return "", fmt.Errorf("unsupported shell: %s. Valid values: bash, zsh, fish", shell)
+}
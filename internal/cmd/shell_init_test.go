@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellInitScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		script, err := shellInitScript(shell)
+		if err != nil {
+			t.Errorf("shellInitScript(%q) failed: %v", shell, err)
+		} else if !strings.Contains(script, "__wkit_cd") {
+			t.Errorf("shellInitScript(%q) does not define __wkit_cd", shell)
+		}
+	}
+
+	if _, err := shellInitScript("tcsh"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
+
+func TestShellInitBash(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("Skipping TestShellInitBash: bash not available")
+	}
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	target := filepath.Join(dir, "feature")
+	removed := filepath.Join(dir, "removed")
+	for _, path := range []string{filepath.Join(root, "sub"), filepath.Join(target, "sub"), removed} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fake wkit that prints what the real one would
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fake := `#!/bin/sh
+case "$1" in
+    switch) if [ "$2" = - ]; then echo "` + root + `/sub"; else echo "` + target + `/sub"; fi ;;
+    add) echo "✓ Created worktree"; echo "` + target + `" ;;
+    root) echo "` + root + `" ;;
+    remove) rmdir "` + removed + `" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "wkit"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := posixShellInit + `
+cd "` + root + `/sub"
+wkit switch feature; echo "switch: $PWD"
+wkit switch -; echo "previous: $PWD"
+wkit add feature; echo "add: $PWD"
+cd "` + removed + `"
+wkit remove removed; echo "remove: $PWD"
+`
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash failed: %v: %s", err, output)
+	}
+
+	expected := strings.Join([]string{
+		"switch: " + filepath.Join(target, "sub"),
+		"previous: " + filepath.Join(root, "sub"),
+		"✓ Created worktree",
+		"add: " + target,
+		"remove: " + root,
+	}, "\n") + "\n"
+	if string(output) != expected {
+		t.Errorf("bash output:\n%s\nwant:\n%s", output, expected)
+	}
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/output"
+	"wkit/internal/worktree"
+)
+
+// stashRecord is the machine-readable form of a stash and the worktree it came from
+type stashRecord struct {
+	worktree.Stash `yaml:",inline"`
+	Worktree       string `json:"worktree,omitempty" yaml:"worktree,omitempty"` // empty when the branch has no worktree
+}
+
+func NewStashCmd() *cobra.Command {
+	stashCmd := &cobra.Command{
+		Use:   "stash",
+		Short: "Inspect stashes across worktrees",
+		Long:  `Stashes are shared by all worktrees of a repository. These commands show which worktree made each stash and apply stashes in a chosen worktree.`,
+	}
+
+	stashCmd.AddCommand(NewStashListCmd())
+	stashCmd.AddCommand(NewStashApplyCmd())
+
+	return stashCmd
+}
+
+func NewStashListCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stashes grouped by the branch and worktree that made them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			stashes, err := manager.ListStashes()
+			if err != nil {
+				return fmt.Errorf("failed to list stashes: %w", err)
+			}
+
+			worktrees, err := manager.ListWorktrees()
+			if err != nil {
+				return fmt.Errorf("failed to list worktrees: %w", err)
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			worktreeByBranch := make(map[string]string)
+			for _, wt := range worktrees {
+				if wt.Branch != "" {
+					worktreeByBranch[wt.Branch] = wt.Path
+				}
+			}
+
+			records := make([]stashRecord, 0, len(stashes))
+			for _, stash := range stashes {
+				records = append(records, stashRecord{Stash: stash, Worktree: worktreeByBranch[stash.Branch]})
+			}
+
+			return output.Write(cmd.OutOrStdout(), format, records, func(w io.Writer) error {
+				if len(records) == 0 {
+					fmt.Fprintln(w, "No stashes found.")
+					return nil
+				}
+				writeStashGroups(w, repoRoot, records, time.Now())
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
+	return cmd
+}
+
+// writeStashGroups writes stashes grouped by branch, in the order each branch first appears
+func writeStashGroups(w io.Writer, repoRoot string, records []stashRecord, now time.Time) {
+	var branches []string
+	groups := make(map[string][]stashRecord)
+	for _, record := range records {
+		if _, ok := groups[record.Branch]; !ok {
+			branches = append(branches, record.Branch)
+		}
+		groups[record.Branch] = append(groups[record.Branch], record)
+	}
+
+	for i, branch := range branches {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		group := groups[branch]
+		label := branch
+		if label == "" {
+			label = "(detached HEAD)"
+		}
+		location := "no worktree"
+		if group[0].Worktree != "" {
+			location = relativeToRoot(repoRoot, group[0].Worktree)
+		}
+		fmt.Fprintf(w, "%s [%s]\n", label, location)
+
+		for _, record := range group {
+			fmt.Fprintf(w, "  %-12s %-16s %s\n", record.Ref, formatAge(record.Created, now), record.Message)
+		}
+	}
+}
+
+func NewStashApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <n>",
+		Short: "Apply a stash in a worktree",
+		Long: `Apply stash <n> (e.g. 2 or stash@{2}) in the worktree given with --to.
+Without --to, the stash is applied in the worktree of the branch it was made on, or in
+the current worktree when that branch has none. The stash is kept in the stash list.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			index, err := parseStashIndex(args[0])
+			if err != nil {
+				return err
+			}
+			target, _ := cmd.Flags().GetString("to")
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			stashes, err := manager.ListStashes()
+			if err != nil {
+				return fmt.Errorf("failed to list stashes: %w", err)
+			}
+			var stash *worktree.Stash
+			for i := range stashes {
+				if stashes[i].Index == index {
+					stash = &stashes[i]
+					break
+				}
+			}
+			if stash == nil {
+				return fmt.Errorf("stash '%s' not found", worktree.StashRef(index))
+			}
+
+			var worktreePath string
+			if target != "" {
+				worktreePath, err = manager.FindWorktreePathStrict(target)
+				if err != nil {
+					return fmt.Errorf("failed to find worktree path: %w", err)
+				}
+			} else {
+				wt, err := manager.FindWorktreeByBranch(stash.Branch)
+				if err != nil {
+					return fmt.Errorf("failed to list worktrees: %w", err)
+				}
+				if stash.Branch == "" || wt == nil {
+					wt, err = manager.CurrentWorktree()
+					if errors.Is(err, worktree.ErrNotInWorktree) {
+						return fmt.Errorf("%s was not made on a branch with a worktree; choose one with --to", stash.Ref)
+					}
+					if err != nil {
+						return err
+					}
+				}
+				worktreePath = wt.Path
+			}
+
+			if err := manager.ApplyStash(worktreePath, *stash); err != nil {
+				return fmt.Errorf("failed to apply stash: %w", err)
+			}
+
+			fmt.Printf("✓ Applied %s to '%s'\n", stash.Ref, worktreePath)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", "", "Worktree to apply the stash in (defaults to the worktree of the stash's branch, then the current worktree)")
+	cmd.RegisterFlagCompletionFunc("to", completeWorktrees)
+	return cmd
+}
+
+// parseStashIndex accepts a stash index such as "2" or a ref such as "stash@{2}"
+func parseStashIndex(arg string) (int, error) {
+	value := arg
+	if inner, ok := strings.CutPrefix(arg, "stash@{"); ok {
+		value = strings.TrimSuffix(inner, "}")
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid stash: %s. Use an index such as 2 or stash@{2}", arg)
+	}
+	return index, nil
+}
+
+// formatAge formats the time elapsed since t, e.g. "5 minutes ago"
+func formatAge(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return pluralAgo(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return pluralAgo(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return pluralAgo(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return pluralAgo(int(d/(30*24*time.Hour)), "month")
+	default:
+		return pluralAgo(int(d/(365*24*time.Hour)), "year")
+	}
+}
+
+func pluralAgo(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
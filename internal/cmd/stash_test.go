@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStashIndex(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected int
+		hasError bool
+	}{
+		{arg: "0", expected: 0},
+		{arg: "2", expected: 2},
+		{arg: "stash@{3}", expected: 3},
+		{arg: "-1", hasError: true},
+		{arg: "stash@{x}", hasError: true},
+		{arg: "latest", hasError: true},
+	}
+
+	for _, tt := range tests {
+		index, err := parseStashIndex(tt.arg)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("parseStashIndex(%q) expected error, got nil", tt.arg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseStashIndex(%q) unexpected error: %v", tt.arg, err)
+		} else if index != tt.expected {
+			t.Errorf("parseStashIndex(%q) = %d, want %d", tt.arg, index, tt.expected)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago      time.Duration
+		expected string
+	}{
+		{ago: 30 * time.Second, expected: "just now"},
+		{ago: time.Minute, expected: "1 minute ago"},
+		{ago: 5 * time.Hour, expected: "5 hours ago"},
+		{ago: 3 * 24 * time.Hour, expected: "3 days ago"},
+		{ago: 65 * 24 * time.Hour, expected: "2 months ago"},
+		{ago: 800 * 24 * time.Hour, expected: "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		if result := formatAge(now.Add(-tt.ago), now); result != tt.expected {
+			t.Errorf("formatAge(-%s) = %q, want %q", tt.ago, result, tt.expected)
+		}
+	}
+}
@@ -1,25 +1,76 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/output"
+	"wkit/internal/tui"
 	"wkit/internal/worktree"
 )
 
+// statusRecord is the machine-readable form of a worktree and its status
+type statusRecord struct {
+	Path                    string `json:"path" yaml:"path"`
+	Branch                  string `json:"branch" yaml:"branch"`
+	HEAD                    string `json:"head" yaml:"head"`
+	worktree.WorktreeStatus `yaml:",inline"`
+	Stashes                 int    `json:"stashes" yaml:"stashes"` // stashes made on the branch
+	Error                   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 func NewStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show git status of all worktrees",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
 			}
 
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			manager.IncludeIgnored, _ = cmd.Flags().GetBool("ignored")
+
+			watch, _ := cmd.Flags().GetBool("watch")
+			if watch && !output.IsTable(format) {
+				return fmt.Errorf("--watch only supports table output")
+			}
+			if watch && !tui.IsTerminal(os.Stdout) {
+				return fmt.Errorf("--watch requires a terminal")
+			}
+
+			parallelism := cfg.Status.Parallelism
+			if cmd.Flags().Changed("parallel") {
+				parallelism, _ = cmd.Flags().GetInt("parallel")
+			}
+			timeout := cfg.Status.Timeout
+			if cmd.Flags().Changed("timeout") {
+				timeout, _ = cmd.Flags().GetDuration("timeout")
+			}
+
 			worktrees, err := manager.ListWorktrees()
 			if err != nil {
 				return fmt.Errorf("failed to list worktrees: %w", err)
@@ -30,54 +81,267 @@ func NewStatusCmd() *cobra.Command {
 				return fmt.Errorf("failed to get repository root: %w", err)
 			}
 
-			fmt.Printf("%-30s %-20s %-12s %-15s\n", "PATH", "BRANCH", "HEAD", "STATUS")
-			fmt.Println(strings.Repeat("-", 80))
+			results := manager.CollectStatuses(cmd.Context(), worktrees, cfg.MainBranch, parallelism, timeout)
 
-			for _, wt := range worktrees {
-				relativePath, err := filepath.Rel(repoRoot, wt.Path)
-				if err != nil {
-					relativePath = wt.Path // Fallback if relative path calculation fails
-				}
-				if relativePath == "." {
-					relativePath = "(root)"
-				}
+			if watch {
+				return watchStatus(cmd.Context(), manager, repoRoot, cfg.MainBranch, timeout, results)
+			}
 
-				status, err := manager.GetWorktreeStatus(wt.Path)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error getting status for %s: %v\n", relativePath, err)
-					continue
-				}
+			stashCounts, err := countStashes(manager)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
 
-				statusStr := ""
-				if status.IsClean {
-					statusStr = "Clean"
-				} else {
-					statusStr = fmt.Sprintf("%dM %dA %dD", status.Modified, status.Added, status.Deleted)
+			records := make([]statusRecord, 0, len(results))
+			for _, result := range results {
+				record := statusRecord{
+					Path:   result.Worktree.Path,
+					Branch: result.Worktree.Branch,
+					HEAD:   result.Worktree.HEAD,
 				}
-
-				fmt.Printf("%-30s %-20s %-12s %-15s\n",
-					relativePath,
-					wt.Branch,
-					wt.HEAD,
-					statusStr,
-				)
-
-				if !status.IsClean {
-					if status.Modified > 0 {
-						fmt.Printf("  📝 %d modified files\n", status.Modified)
-					}
-					if status.Added > 0 {
-						fmt.Printf("  ➕ %d added files\n", status.Added)
-					}
-					if status.Deleted > 0 {
-						fmt.Printf("  ❌ %d deleted files\n", status.Deleted)
-					}
-					if status.Untracked > 0 {
-						fmt.Printf("  ❓ %d untracked files\n", status.Untracked)
-					}
+				if result.Worktree.Branch != "" {
+					record.Stashes = stashCounts[result.Worktree.Branch]
+				}
+				if result.Err != nil {
+					record.Error = result.Err.Error()
+				} else {
+					record.WorktreeStatus = *result.Status
 				}
+				records = append(records, record)
 			}
-			return nil
+
+			return output.Write(cmd.OutOrStdout(), format, records, func(w io.Writer) error {
+				return writeStatusTable(w, os.Stderr, repoRoot, results, stashCounts)
+			})
 		},
 	}
+
+	cmd.Flags().IntP("parallel", "j", 0, "Number of worktrees to inspect concurrently (defaults to config status.parallelism)")
+	cmd.Flags().Duration("timeout", 0, "Per-worktree status timeout (defaults to config status.timeout)")
+	cmd.Flags().Bool("ignored", false, "Also count ignored files (slower in worktrees with large ignored directories)")
+	cmd.Flags().BoolP("watch", "w", false, "Keep a full-screen status table updated as files change")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
+	return cmd
+}
+
+// watchDebounce is how long a worktree must stay unchanged before its status is recomputed
+const watchDebounce = 300 * time.Millisecond
+
+// watchStatus redraws the status table whenever files change, recomputing the status of
+// only the worktree that changed, until interrupted
+func watchStatus(ctx context.Context, manager *worktree.Manager, repoRoot string, mainBranch string, timeout time.Duration, results []worktree.StatusResult) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	screen := tui.NewScreen(os.Stdout)
+	defer screen.Close()
+
+	var mu sync.Mutex
+	var unwatched int
+	var unwatchedErr error
+	draw := func() {
+		stashCounts, _ := countStashes(manager)
+
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "wkit status --watch    updated %s    (Ctrl+C to quit)\n", time.Now().Format("15:04:05"))
+		if unwatched > 0 {
+			// Changes in these directories are missed until the next refresh of their worktree
+			fmt.Fprintf(&buf, "Warning: %d directories are not watched: %v\n", unwatched, unwatchedErr)
+		}
+		fmt.Fprintln(&buf)
+		writeStatusTable(&buf, io.Discard, repoRoot, results, stashCounts)
+		screen.Draw(buf.String())
+	}
+
+	mu.Lock()
+	draw()
+	mu.Unlock()
+
+	worktrees := make([]worktree.Worktree, len(results))
+	for i, result := range results {
+		worktrees[i] = result.Worktree
+	}
+
+	return worktree.WatchWorktrees(ctx, worktrees, watchDebounce, func(index int) {
+		result := manager.CollectStatuses(ctx, worktrees[index:index+1], mainBranch, 1, timeout)[0]
+
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		results[index] = result
+		draw()
+	}, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		unwatched++
+		if unwatched == 1 {
+			// Later warnings show up with the next redraw; hitting the inotify limit yields many
+			unwatchedErr = err
+			draw()
+		}
+	})
+}
+
+// countStashes counts the stashes made on each branch
+func countStashes(manager *worktree.Manager) (map[string]int, error) {
+	stashes, err := manager.ListStashes()
+	if err != nil {
+		return map[string]int{}, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return worktree.CountStashesByBranch(stashes), nil
+}
+
+// writeStatusTable writes the human-readable status table with per-worktree details.
+// Details of failed worktrees go to errW.
+func writeStatusTable(w io.Writer, errW io.Writer, repoRoot string, results []worktree.StatusResult, stashCounts map[string]int) error {
+	fmt.Fprintf(w, "%-30s %-20s %-12s %-12s %-15s\n", "PATH", "BRANCH", "HEAD", "MAIN", "STATUS")
+	fmt.Fprintln(w, strings.Repeat("-", 93))
+
+	for _, result := range results {
+		wt := result.Worktree
+		relativePath, err := filepath.Rel(repoRoot, wt.Path)
+		if err != nil {
+			relativePath = wt.Path // Fallback if relative path calculation fails
+		}
+		if relativePath == "." {
+			relativePath = "(root)"
+		}
+
+		status := result.Status
+		if result.Err != nil {
+			statusStr := "Error"
+			if errors.Is(result.Err, worktree.ErrStatusTimeout) {
+				statusStr = "Timeout"
+			}
+			fmt.Fprintf(w, "%-30s %-20s %-12s %-12s %-15s\n", relativePath, wt.Branch, wt.HEAD, "-", statusStr)
+			fmt.Fprintf(errW, "Error getting status for %s: %v\n", relativePath, result.Err)
+			continue
+		}
+
+		statusStr := formatStatusSummary(status)
+
+		fmt.Fprintf(w, "%-30s %-20s %-12s %-12s %-15s\n",
+			relativePath,
+			wt.Branch,
+			wt.HEAD,
+			formatMainDivergence(status.MainRef, status.MainAhead, status.MainBehind),
+			statusStr,
+		)
+
+		if stashCount := stashCounts[wt.Branch]; wt.Branch != "" && stashCount > 0 {
+			fmt.Fprintf(w, "  📦 %d stashed\n", stashCount)
+		}
+		if status.Operation != "" {
+			fmt.Fprintf(w, "  🚧 %s in progress; %s\n", status.Operation, operationHint(status.Operation))
+		}
+		if !status.IsClean {
+			if status.Conflicted > 0 {
+				fmt.Fprintf(w, "  ⚠️  %d conflicted files\n", status.Conflicted)
+			}
+			fmt.Fprintf(w, "  📋 %d staged, %d unstaged\n", status.Staged, status.Unstaged)
+			if status.Modified > 0 {
+				fmt.Fprintf(w, "  📝 %d modified files\n", status.Modified)
+			}
+			if status.Added > 0 {
+				fmt.Fprintf(w, "  ➕ %d added files\n", status.Added)
+			}
+			if status.Deleted > 0 {
+				fmt.Fprintf(w, "  ❌ %d deleted files\n", status.Deleted)
+			}
+			if status.Renamed > 0 {
+				fmt.Fprintf(w, "  🔀 %d renamed files\n", status.Renamed)
+			}
+			if status.Copied > 0 {
+				fmt.Fprintf(w, "  📄 %d copied files\n", status.Copied)
+			}
+			if status.Untracked > 0 {
+				fmt.Fprintf(w, "  ❓ %d untracked files\n", status.Untracked)
+			}
+			if status.Ignored > 0 {
+				fmt.Fprintf(w, "  🙈 %d ignored files\n", status.Ignored)
+			}
+		}
+	}
+	return nil
+}
+
+// formatStatusSummary formats the STATUS column, e.g. "REBASING 3/7 2M 1A 0D 1U ↑1 ↓2"
+func formatStatusSummary(status *worktree.WorktreeStatus) string {
+	var parts []string
+	if status.Operation != "" {
+		parts = append(parts, formatOperation(status.Operation, status.OperationStep, status.OperationTotal))
+	}
+	if status.IsClean {
+		parts = append(parts, "Clean")
+	} else {
+		parts = append(parts, fmt.Sprintf("%dM %dA %dD", status.Modified, status.Added, status.Deleted))
+		if status.Renamed > 0 {
+			parts = append(parts, fmt.Sprintf("%dR", status.Renamed))
+		}
+		if status.Conflicted > 0 {
+			parts = append(parts, fmt.Sprintf("%dU", status.Conflicted))
+		}
+	}
+	if status.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", status.Ahead))
+	}
+	if status.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", status.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatMainDivergence formats the MAIN column, e.g. "↑3 ↓5", "=" when even, "-" when unknown
+func formatMainDivergence(mainRef string, ahead int, behind int) string {
+	if mainRef == "" {
+		return "-"
+	}
+	if ahead == 0 && behind == 0 {
+		return "="
+	}
+
+	var parts []string
+	if ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", ahead))
+	}
+	if behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatOperation formats an in-progress operation, e.g. "REBASING 3/7" or "MERGING"
+func formatOperation(operation string, step int, total int) string {
+	var label string
+	switch operation {
+	case worktree.OperationRebase:
+		label = "REBASING"
+	case worktree.OperationAm:
+		label = "APPLYING"
+	case worktree.OperationMerge:
+		label = "MERGING"
+	case worktree.OperationCherryPick:
+		label = "CHERRY-PICKING"
+	case worktree.OperationRevert:
+		label = "REVERTING"
+	case worktree.OperationBisect:
+		label = "BISECTING"
+	default:
+		label = strings.ToUpper(operation)
+	}
+
+	if total > 0 {
+		return fmt.Sprintf("%s %d/%d", label, step, total)
+	}
+	return label
+}
+
+// operationHint tells how to finish or abandon an operation
+func operationHint(operation string) string {
+	if operation == worktree.OperationBisect {
+		return "run 'git bisect reset' when done"
+	}
+	return fmt.Sprintf("run 'git %s --continue' or 'git %s --abort'", operation, operation)
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"testing"
+
+	"wkit/internal/worktree"
+)
+
+func TestFormatStatusSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   worktree.WorktreeStatus
+		expected string
+	}{
+		{
+			name:     "clean",
+			status:   worktree.WorktreeStatus{IsClean: true},
+			expected: "Clean",
+		},
+		{
+			name:     "clean but ahead and behind",
+			status:   worktree.WorktreeStatus{IsClean: true, Ahead: 1, Behind: 2},
+			expected: "Clean ↑1 ↓2",
+		},
+		{
+			name:     "dirty with renames and conflicts",
+			status:   worktree.WorktreeStatus{Modified: 2, Added: 1, Renamed: 1, Conflicted: 3},
+			expected: "2M 1A 0D 1R 3U",
+		},
+		{
+			name:     "rebase in progress",
+			status:   worktree.WorktreeStatus{Conflicted: 1, Operation: worktree.OperationRebase, OperationStep: 3, OperationTotal: 7},
+			expected: "REBASING 3/7 0M 0A 0D 1U",
+		},
+		{
+			name:     "merge in progress",
+			status:   worktree.WorktreeStatus{IsClean: true, Operation: worktree.OperationMerge},
+			expected: "MERGING Clean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatStatusSummary(&tt.status); result != tt.expected {
+				t.Errorf("formatStatusSummary() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatMainDivergence(t *testing.T) {
+	tests := []struct {
+		name     string
+		mainRef  string
+		ahead    int
+		behind   int
+		expected string
+	}{
+		{
+			name:     "no remote main branch",
+			expected: "-",
+		},
+		{
+			name:     "even with main",
+			mainRef:  "origin/main",
+			expected: "=",
+		},
+		{
+			name:     "ahead and behind main",
+			mainRef:  "origin/main",
+			ahead:    3,
+			behind:   5,
+			expected: "↑3 ↓5",
+		},
+		{
+			name:     "only behind main",
+			mainRef:  "origin/main",
+			behind:   2,
+			expected: "↓2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatMainDivergence(tt.mainRef, tt.ahead, tt.behind); result != tt.expected {
+				t.Errorf("formatMainDivergence() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
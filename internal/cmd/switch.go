@@ -1,43 +1,190 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
+// recentRecord is the machine-readable form of a worktree in switch --recent
+type recentRecord struct {
+	Branch  string    `json:"branch" yaml:"branch"`
+	Path    string    `json:"path" yaml:"path"`
+	Visited time.Time `json:"visited" yaml:"visited"`
+}
+
 func NewSwitchCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "switch <worktree>",
+	var recent bool
+	var create bool
+	var baseBranch string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "switch [worktree | -]",
 		Short: "Switch to a worktree",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			worktreeName := args[0]
+		Long: `Print the path of a worktree for shell wrappers. Without an argument, pick the worktree interactively.
+
+'wkit switch -' goes back to the previously visited worktree, like 'cd -', and
+'wkit switch --recent' lists recently visited worktrees. Switches made with switch
+and add are recorded in a history shared by every worktree of the repository.
 
+With --create, a worktree that does not exist is created like 'wkit add' would,
+including copy_files and the base branch, and its path is printed. Names must then
+match a branch, path or directory exactly, so a new branch is never mistaken for a
+prefix or fuzzy match of an existing one. With config switch.create_missing, names
+are resolved as usual and only a name that matches no worktree at all is created.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeWorktrees,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
 			}
 
-			worktreePath, err := manager.FindWorktreePath(worktreeName)
-			if err != nil {
-				return fmt.Errorf("failed to find worktree path: %w", err)
+			if recent {
+				if len(args) > 0 {
+					return fmt.Errorf("--recent does not take a worktree argument")
+				}
+				return writeRecentWorktrees(cmd.OutOrStdout(), manager, format)
+			}
+			if format != "" {
+				return fmt.Errorf("--format can only be used with --recent")
 			}
 
-			relativePath, err := worktree.GetRelativePathFromRoot()
-			if err != nil {
-				// If we can't get relative path, just output the worktree path
-				fmt.Println(worktreePath)
-				return nil
+			// The config is only loaded when it matters, so a broken config never breaks
+			// switching to an existing worktree
+			var cfg *config.Config
+			loadConfig := func() (*config.Config, error) {
+				if cfg == nil {
+					loaded, err := config.Load()
+					if err != nil {
+						return nil, fmt.Errorf("failed to load config: %w", err)
+					}
+					cfg = loaded
+				}
+				return cfg, nil
+			}
+			if baseBranch != "" && !create {
+				if _, err := loadConfig(); err != nil {
+					return err
+				}
+				if !cfg.Switch.CreateMissing {
+					return fmt.Errorf("--base-branch can only be used with --create")
+				}
 			}
 
-			if relativePath != "" {
-				fmt.Printf("%s:%s\n", worktreePath, relativePath)
-			} else {
-				fmt.Println(worktreePath)
+			var worktreePath string
+			switch {
+			case len(args) == 1 && args[0] == "-":
+				worktreePath, err = manager.PreviousWorktree()
+			case len(args) == 1 && create:
+				worktreePath, err = manager.FindWorktreePathExact(args[0])
+				if err == nil && worktreePath == "" {
+					if _, err := loadConfig(); err != nil {
+						return err
+					}
+					worktreePath, err = createSwitchTarget(manager, cfg, args[0], baseBranch)
+				}
+			default:
+				worktreePath, err = resolveWorktreeArg(manager, args)
+				var notFound *worktree.WorktreeNotFoundError
+				if errors.As(err, &notFound) {
+					if _, err := loadConfig(); err != nil {
+						return err
+					}
+					// With switch.create_missing, only names that match nothing at all are created
+					if cfg.Switch.CreateMissing {
+						worktreePath, err = createSwitchTarget(manager, cfg, args[0], baseBranch)
+					}
+				}
+			}
+			if err != nil {
+				return err
 			}
+
+			recordSwitch(manager, worktreePath)
+			visitIndex(manager, worktreePath)
+			printSwitchTarget(cmd.OutOrStdout(), worktreePath)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&recent, "recent", false, "List recently visited worktrees, most recent first")
+	cmd.Flags().BoolVarP(&create, "create", "c", false, "Create the worktree when it does not exist, like wkit add")
+	cmd.Flags().StringVarP(&baseBranch, "base-branch", "b", "", "Base branch for a created branch (defaults to config main_branch)")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage+" (with --recent)")
+	cmd.RegisterFlagCompletionFunc("base-branch", completeBranches)
+	return cmd
+}
+
+// createSwitchTarget creates the worktree for branch like wkit add and returns its path.
+// Progress goes to stderr so stdout is only the path for shell wrappers.
+func createSwitchTarget(manager *worktree.Manager, cfg *config.Config, branch string, baseBranch string) (string, error) {
+	record, err := createWorktree(manager, cfg, addOptions{branch: branch, baseBranch: baseBranch, onConflict: "fail"}, os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return record.Path, nil
+}
+
+// writeRecentWorktrees writes the switch history of the repository
+func writeRecentWorktrees(out io.Writer, manager *worktree.Manager, format string) error {
+	if err := output.Validate(format); err != nil {
+		return err
+	}
+
+	recent, err := manager.RecentWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to read switch history: %w", err)
+	}
+
+	repoRoot, err := worktree.GetRepositoryRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	records := make([]recentRecord, 0, len(recent))
+	for _, r := range recent {
+		records = append(records, recentRecord{Branch: r.Worktree.Branch, Path: r.Worktree.Path, Visited: r.Visited})
+	}
+
+	return output.Write(out, format, records, func(w io.Writer) error {
+		if len(records) == 0 {
+			fmt.Fprintln(w, "No recently visited worktrees.")
+			return nil
+		}
+
+		now := time.Now()
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "BRANCH\tPATH\tVISITED")
+		for _, record := range records {
+			branch := record.Branch
+			if branch == "" {
+				branch = "(detached)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", branch, relativeToRoot(repoRoot, record.Path), formatAge(record.Visited, now))
+		}
+		return tw.Flush()
+	})
+}
+
+// recordSwitch adds a switch to worktreePath to the history used by 'switch -' and --recent
+func recordSwitch(manager *worktree.Manager, worktreePath string) {
+	if err := manager.RecordSwitch(worktreePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record switch history: %v\n", err)
+	}
+}
+
+// printSwitchTarget prints the directory to change to for shell wrappers: the current
+// subdirectory in the target worktree, or its deepest existing ancestor
+func printSwitchTarget(w io.Writer, worktreePath string) {
+	fmt.Fprintln(w, worktree.TargetDirectory(worktreePath))
 }
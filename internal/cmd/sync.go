@@ -2,19 +2,42 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"wkit/internal/config"
+	"wkit/internal/output"
 	"wkit/internal/worktree"
 )
 
+// syncRecord is the machine-readable result of syncing a worktree
+type syncRecord struct {
+	Path       string `json:"path" yaml:"path"`
+	MainBranch string `json:"main_branch" yaml:"main_branch"`
+	Strategy   string `json:"strategy" yaml:"strategy"`
+}
+
 func NewSyncCmd() *cobra.Command {
+	var format string
+
 	cmd := &cobra.Command{
-		Use:   "sync [worktree]",
-		Short: "Sync worktree with main branch",
-		Args:  cobra.MaximumNArgs(1),
+		Use:               "sync [worktree]",
+		Short:             "Sync worktree with main branch",
+		Long:              `Sync a worktree with the main branch. Without an argument, sync the current worktree, or pick one interactively when not inside a worktree.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeWorktrees,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.Validate(format); err != nil {
+				return err
+			}
+
+			// Keep stdout machine-readable by sending progress messages to stderr
+			var msg io.Writer = os.Stdout
+			if !output.IsTable(format) {
+				msg = os.Stderr
+			}
+
 			manager, err := worktree.NewManager()
 			if err != nil {
 				return fmt.Errorf("failed to create manager: %w", err)
@@ -25,32 +48,18 @@ func NewSyncCmd() *cobra.Command {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			var targetWorktreePath string
-			if len(args) > 0 {
-				targetWorktreePath, err = manager.FindWorktreePath(args[0])
-				if err != nil {
-					return fmt.Errorf("failed to find worktree path: %w", err)
-				}
-			} else {
-				currentDir, err := os.Getwd()
-				if err != nil {
-					return fmt.Errorf("failed to get current directory: %w", err)
-				}
-				worktrees, err := manager.ListWorktrees()
-				if err != nil {
-					return fmt.Errorf("failed to list worktrees: %w", err)
-				}
-				found := false
-				for _, wt := range worktrees {
-					if wt.Path == currentDir {
-						targetWorktreePath = currentDir
-						found = true
-						break
-					}
-				}
-				if !found {
-					return fmt.Errorf("current directory is not a worktree")
-				}
+			targetWorktreePath, err := resolveWorktreeArgOrCurrentStrict(manager, args)
+			if err != nil {
+				return err
+			}
+
+			// Merging or rebasing on top of an unfinished operation would bury it
+			operation, _, _, err := worktree.DetectOperation(targetWorktreePath)
+			if err != nil {
+				return fmt.Errorf("failed to inspect worktree: %w", err)
+			}
+			if operation != "" {
+				return fmt.Errorf("worktree '%s' has a %s in progress; %s before syncing", targetWorktreePath, operation, operationHint(operation))
 			}
 
 			rebaseFlag, _ := cmd.Flags().GetBool("rebase")
@@ -60,7 +69,7 @@ func NewSyncCmd() *cobra.Command {
 				syncStrategy = "rebase"
 			}
 
-			fmt.Printf("Syncing worktree '%s' with %s branch using %s...\n",
+			fmt.Fprintf(msg, "Syncing worktree '%s' with %s branch using %s...\n",
 				targetWorktreePath, cfg.MainBranch, syncStrategy)
 
 			err = manager.SyncWorktreeWithBranch(targetWorktreePath, cfg.MainBranch, useRebase)
@@ -68,11 +77,15 @@ func NewSyncCmd() *cobra.Command {
 				return fmt.Errorf("failed to sync worktree: %w", err)
 			}
 
-			fmt.Printf("✓ Successfully synced worktree '%s'\n", targetWorktreePath)
-			return nil
+			record := syncRecord{Path: targetWorktreePath, MainBranch: cfg.MainBranch, Strategy: syncStrategy}
+			return output.Write(cmd.OutOrStdout(), format, record, func(w io.Writer) error {
+				fmt.Fprintf(w, "✓ Successfully synced worktree '%s'\n", targetWorktreePath)
+				return nil
+			})
 		},
 	}
 
 	cmd.Flags().BoolP("rebase", "r", false, "Use rebase instead of merge")
+	cmd.Flags().StringVar(&format, "format", "", output.FlagUsage)
 	return cmd
 }
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wkit/internal/config"
+	"wkit/internal/tui"
+	"wkit/internal/worktree"
+)
+
+// Reverse video highlights the selected row
+const (
+	highlightStart = "\x1b[7m"
+	highlightEnd   = "\x1b[0m"
+)
+
+func NewUICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ui",
+		Short: "Open an interactive dashboard of all worktrees",
+		Long: `Open a full-screen dashboard listing every worktree with its status,
+upstream ahead/behind, divergence from the remote main branch and last commit age.
+
+Keys:
+  j/k, ↑/↓   move the selection
+  s          sync the selected worktree with the main branch
+  d          remove the selected worktree
+  l          lock or unlock the selected worktree
+  o, Enter   open a shell in the selected worktree
+  v          view the diff of the selected worktree
+  r          refresh
+  q          quit`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !tui.IsTerminal(os.Stdin) || !tui.IsTerminal(os.Stdout) {
+				return fmt.Errorf("ui requires a terminal")
+			}
+
+			manager, err := worktree.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			repoRoot, err := worktree.GetRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("failed to get repository root: %w", err)
+			}
+
+			d := &dashboard{manager: manager, cfg: cfg, repoRoot: repoRoot}
+			if err := d.load(cmd.Context()); err != nil {
+				return err
+			}
+			return d.run(cmd.Context())
+		},
+	}
+}
+
+// dashboard is the state of wkit ui
+type dashboard struct {
+	manager  *worktree.Manager
+	cfg      *config.Config
+	repoRoot string
+	rows     []dashboardRow
+	selected int
+	message  string
+
+	screen  *tui.Screen
+	restore func()
+}
+
+type dashboardRow struct {
+	result worktree.StatusResult
+	commit *worktree.CommitInfo
+}
+
+// load collects the worktrees and their status, keeping the selection on the same worktree.
+// The first load selects the current worktree.
+func (d *dashboard) load(ctx context.Context) error {
+	var selectedPath string
+	if d.selected < len(d.rows) {
+		selectedPath = d.rows[d.selected].result.Worktree.Path
+	} else if current, err := d.manager.CurrentWorktree(); err == nil {
+		selectedPath = current.Path
+	}
+
+	worktrees, err := d.manager.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	results := d.manager.CollectStatuses(ctx, worktrees, d.cfg.MainBranch, d.cfg.Status.Parallelism, d.cfg.Status.Timeout)
+	d.rows = make([]dashboardRow, len(results))
+	d.selected = 0
+	for i, result := range results {
+		d.rows[i].result = result
+		if info, err := d.manager.GetCommitInfo(result.Worktree.Path); err == nil {
+			d.rows[i].commit = info
+		}
+		if result.Worktree.Path == selectedPath {
+			d.selected = i
+		}
+	}
+	return nil
+}
+
+// run draws the dashboard and handles keys until the user quits
+func (d *dashboard) run(ctx context.Context) error {
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
+
+	for {
+		d.screen.Draw(d.render())
+
+		key, err := tui.ReadKey(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		d.message = ""
+		switch {
+		case key == tui.KeyCtrlC || key == tui.KeyEscape || key.Rune == 'q':
+			return nil
+		case key == tui.KeyDown || key.Rune == 'j':
+			if d.selected < len(d.rows)-1 {
+				d.selected++
+			}
+		case key == tui.KeyUp || key.Rune == 'k':
+			if d.selected > 0 {
+				d.selected--
+			}
+		case key.Rune == 'r':
+			if err := d.load(ctx); err != nil {
+				d.message = err.Error()
+			}
+		case len(d.rows) == 0:
+			continue
+		case key.Rune == 's':
+			d.runAction(ctx, true, func(worktreePath string) error {
+				return runSubcommand(ctx, NewSyncCmd(), worktreePath)
+			})
+		case key.Rune == 'd':
+			if d.confirmRemove() {
+				d.runAction(ctx, true, func(worktreePath string) error {
+					return runSubcommand(ctx, NewRemoveCmd(), worktreePath)
+				})
+			}
+		case key.Rune == 'l':
+			if d.rows[d.selected].result.Worktree.Locked {
+				d.runAction(ctx, false, func(worktreePath string) error {
+					return runSubcommand(ctx, NewUnlockCmd(), worktreePath)
+				})
+			} else {
+				d.runAction(ctx, false, func(worktreePath string) error {
+					return runSubcommand(ctx, NewLockCmd(), worktreePath, "--reason", "locked from wkit ui")
+				})
+			}
+		case key == tui.KeyEnter || key.Rune == 'o':
+			d.runAction(ctx, false, func(worktreePath string) error {
+				fmt.Printf("Opening a shell in '%s'; exit it to return to wkit ui\n", worktreePath)
+				return runInWorktree(worktreePath, shellPath())
+			})
+		case key.Rune == 'v':
+			d.runAction(ctx, true, func(worktreePath string) error {
+				return runInWorktree(worktreePath, "git", "diff", "HEAD")
+			})
+		}
+	}
+}
+
+// enter switches the terminal to raw mode and the full-screen dashboard
+func (d *dashboard) enter() error {
+	restore, err := tui.MakeRaw(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	d.restore = restore
+	d.screen = tui.NewScreen(os.Stdout)
+	return nil
+}
+
+// leave restores the terminal to how it was before enter
+func (d *dashboard) leave() {
+	d.screen.Close()
+	d.restore()
+}
+
+// runAction leaves the dashboard to run action on the path of the selected worktree with
+// the terminal in its normal state, optionally waiting for Enter so its output can be read,
+// then reloads. Subcommands get the path rather than the branch, so they act on the exact
+// row that was selected.
+func (d *dashboard) runAction(ctx context.Context, pause bool, action func(worktreePath string) error) {
+	worktreePath := d.rows[d.selected].result.Worktree.Path
+
+	d.leave()
+	if err := action(worktreePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		pause = true
+	}
+	if pause {
+		fmt.Print("\nPress Enter to return to wkit ui...")
+		bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+
+	if err := d.enter(); err != nil {
+		// Without raw mode the dashboard cannot read keys any more
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := d.load(ctx); err != nil {
+		d.message = err.Error()
+	}
+}
+
+// confirmRemove asks for confirmation on the message line before removing the selected worktree
+func (d *dashboard) confirmRemove() bool {
+	row := d.rows[d.selected]
+	name := relativeToRoot(d.repoRoot, row.result.Worktree.Path)
+	d.message = fmt.Sprintf("Remove worktree '%s'? (y/N)", name)
+	if row.result.Status != nil && !row.result.Status.IsClean {
+		d.message = fmt.Sprintf("Remove worktree '%s'? Its uncommitted changes will be lost (y/N)", name)
+	}
+	d.screen.Draw(d.render())
+
+	key, err := tui.ReadKey(os.Stdin)
+	d.message = ""
+	return err == nil && (key.Rune == 'y' || key.Rune == 'Y')
+}
+
+// render formats the dashboard to fit the terminal, scrolling to keep the selection visible
+func (d *dashboard) render() string {
+	width, height := tui.Size(os.Stdout)
+	now := time.Now()
+
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  BRANCH\tPATH\tSTATUS\tMAIN\tAGE\tLOCK")
+	for _, row := range d.rows {
+		wt := row.result.Worktree
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+
+		statusStr, mainStr := "Error", "-"
+		if status := row.result.Status; status != nil {
+			statusStr = formatStatusSummary(status)
+			mainStr = formatMainDivergence(status.MainRef, status.MainAhead, status.MainBehind)
+		}
+
+		age := ""
+		if row.commit != nil {
+			age = formatAge(row.commit.Committed, now)
+		}
+		lock := ""
+		if wt.Locked {
+			lock = "🔒"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", branch, relativeToRoot(d.repoRoot, wt.Path), statusStr, mainStr, age, lock)
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	header, rows := lines[0], lines[1:]
+
+	// Title, blank line, header, blank line, message and key help take 6 lines
+	visible := height - 6
+	if visible < 1 {
+		visible = 1
+	}
+	first := 0
+	if d.selected >= visible {
+		first = d.selected - visible + 1
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, tui.Truncate(fmt.Sprintf("wkit ui — %s", d.repoRoot), width))
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, tui.Truncate(header, width))
+	if len(rows) == 0 {
+		fmt.Fprintln(&b, "  No worktrees found.")
+	}
+	for i := first; i < len(rows) && i < first+visible; i++ {
+		line := tui.Truncate(rows[i], width)
+		if i == d.selected {
+			line = highlightStart + ">" + strings.TrimPrefix(line, " ") + highlightEnd
+		}
+		fmt.Fprintln(&b, line)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, tui.Truncate(d.message, width))
+	fmt.Fprint(&b, tui.Truncate("j/k move  s sync  d remove  l lock/unlock  o shell  v diff  r refresh  q quit", width))
+	return b.String()
+}
+
+// runSubcommand runs a wkit command as if it was invoked with args
+func runSubcommand(ctx context.Context, c *cobra.Command, args ...string) error {
+	c.SetArgs(args)
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	return c.ExecuteContext(ctx)
+}
+
+// runInWorktree runs a program in a worktree attached to the terminal
+func runInWorktree(worktreePath string, name string, args ...string) error {
+	c := exec.Command(name, args...)
+	c.Dir = worktreePath
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// shellPath returns the user's shell
+func shellPath() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
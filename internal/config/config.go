@@ -1,10 +1,15 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,10 +17,13 @@ import (
 // Config represents the application configuration
 type Config struct {
 	WkitRoot            string    `mapstructure:"wkit_root"`
+	PathTemplate        string    `mapstructure:"path_template"`
 	AutoCleanup         bool      `mapstructure:"auto_cleanup"`
 	DefaultSyncStrategy string    `mapstructure:"default_sync_strategy"`
 	MainBranch          string    `mapstructure:"main_branch"`
 	CopyFiles           CopyFiles `mapstructure:"copy_files"`
+	Status              Status    `mapstructure:"status"`
+	Switch              Switch    `mapstructure:"switch"`
 }
 
 // CopyFiles represents the configuration for copying files
@@ -24,6 +32,17 @@ type CopyFiles struct {
 	Files   []string `mapstructure:"files"`
 }
 
+// Status represents the configuration for collecting worktree statuses
+type Status struct {
+	Parallelism int           `mapstructure:"parallelism"` // 0 means one per CPU
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+// Switch represents the configuration for wkit switch
+type Switch struct {
+	CreateMissing bool `mapstructure:"create_missing"` // create worktrees that do not exist, like switch --create
+}
+
 // Load loads the configuration from local or global config files
 func Load() (*Config, error) {
 	v := viper.New()
@@ -35,11 +54,15 @@ func Load() (*Config, error) {
 
 	// Set default values
 	v.SetDefault("wkit_root", ".git/.wkit-worktrees")
+	v.SetDefault("path_template", "")
 	v.SetDefault("auto_cleanup", false)
 	v.SetDefault("default_sync_strategy", "merge")
 	v.SetDefault("main_branch", "main")
 	v.SetDefault("copy_files.enabled", false)
 	v.SetDefault("copy_files.files", []string{".envrc", "compose.override.yaml", ".env.local", "config/local.yaml"})
+	v.SetDefault("status.parallelism", 0)
+	v.SetDefault("status.timeout", "10s")
+	v.SetDefault("switch.create_missing", false)
 
 	// Read global config
 	if err := v.ReadInConfig(); err != nil {
@@ -92,11 +115,15 @@ func SaveGlobal(cfg *Config) error {
 
 	// Set values from the provided config struct
 	v.Set("wkit_root", cfg.WkitRoot)
+	v.Set("path_template", cfg.PathTemplate)
 	v.Set("auto_cleanup", cfg.AutoCleanup)
 	v.Set("default_sync_strategy", cfg.DefaultSyncStrategy)
 	v.Set("main_branch", cfg.MainBranch)
 	v.Set("copy_files.enabled", cfg.CopyFiles.Enabled)
 	v.Set("copy_files.files", cfg.CopyFiles.Files)
+	v.Set("status.parallelism", cfg.Status.Parallelism)
+	v.Set("status.timeout", cfg.Status.Timeout.String())
+	v.Set("switch.create_missing", cfg.Switch.CreateMissing)
 
 	configPath := filepath.Join(configDir, "config.yaml")
 	if err := v.WriteConfigAs(configPath); err != nil {
@@ -115,11 +142,15 @@ func InitLocal() error {
 
 	// Set default values
 	v.SetDefault("wkit_root", ".git/.wkit-worktrees")
+	v.SetDefault("path_template", "")
 	v.SetDefault("auto_cleanup", false)
 	v.SetDefault("default_sync_strategy", "merge")
 	v.SetDefault("main_branch", "main")
 	v.SetDefault("copy_files.enabled", false)
 	v.SetDefault("copy_files.files", []string{".envrc", "compose.override.yaml", ".env.local", "config/local.yaml"})
+	v.SetDefault("status.parallelism", 0)
+	v.SetDefault("status.timeout", "10s")
+	v.SetDefault("switch.create_missing", false)
 
 	if err := v.SafeWriteConfigAs(".wkit.yaml"); err != nil {
 		return fmt.Errorf("failed to create local config file: %w", err)
@@ -130,21 +161,131 @@ func InitLocal() error {
 
 // ResolveWorktreePath resolves the worktree path based on config and provided path
 // Deprecated: Use ResolveWkitPath instead
-func (c *Config) ResolveWorktreePath(branch string, providedPath string, repositoryRoot string) string {
+func (c *Config) ResolveWorktreePath(branch string, providedPath string, repositoryRoot string) (string, error) {
 	return c.ResolveWkitPath(branch, providedPath, repositoryRoot)
 }
 
-// ResolveWkitPath resolves the worktree path based on wkit_root config and provided path
-func (c *Config) ResolveWkitPath(branch string, providedPath string, repositoryRoot string) string {
+// ResolveWkitPath resolves the worktree path based on wkit_root, path_template and provided path
+func (c *Config) ResolveWkitPath(branch string, providedPath string, repositoryRoot string) (string, error) {
 	if providedPath != "" {
-		return providedPath
+		return providedPath, nil
 	}
 
-	if filepath.IsAbs(c.WkitRoot) {
-		return filepath.Join(c.WkitRoot, branch)
-	} else {
-		return filepath.Join(repositoryRoot, c.WkitRoot, branch)
+	if c.PathTemplate == "" {
+		return filepath.Join(c.ResolveWkitRoot(repositoryRoot), branch), nil
+	}
+
+	path, err := ExecutePathTemplate(c.PathTemplate, NewPathTemplateData(branch, repositoryRoot))
+	if err != nil {
+		return "", err
+	}
+
+	// Relative templates are placed under wkit_root
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.ResolveWkitRoot(repositoryRoot), path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// ResolvePathTemplateRoot returns the deepest directory that path_template places every
+// worktree of the repository under, or "" without a template. It is found by rendering the
+// template for two branches and keeping the directories their paths share.
+func (c *Config) ResolvePathTemplateRoot(repositoryRoot string) string {
+	if c.PathTemplate == "" {
+		return ""
+	}
+
+	first, err := c.ResolveWkitPath("wkit-root-probe-a", "", repositoryRoot)
+	if err != nil {
+		return ""
+	}
+	second, err := c.ResolveWkitPath("wkit-root-probe-b", "", repositoryRoot)
+	if err != nil {
+		return ""
+	}
+
+	root := filepath.Dir(first)
+	for !strings.HasPrefix(second, root+string(filepath.Separator)) && root != filepath.Dir(root) {
+		root = filepath.Dir(root)
+	}
+	return root
+}
+
+// ResolveWkitRoot resolves the absolute directory that holds wkit-managed worktrees
+func (c *Config) ResolveWkitRoot(repositoryRoot string) string {
+	root := expandPath(c.WkitRoot)
+	if filepath.IsAbs(root) {
+		return root
+	}
+	return filepath.Join(repositoryRoot, root)
+}
+
+// PathTemplateData holds the fields available to path_template
+type PathTemplateData struct {
+	Repo       string
+	Branch     string
+	BranchSlug string
+	User       string
+	Date       string
+}
+
+// NewPathTemplateData builds the template fields for a branch of the repository at repositoryRoot
+func NewPathTemplateData(branch string, repositoryRoot string) PathTemplateData {
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		username = u.Username
+	}
+
+	return PathTemplateData{
+		Repo:       filepath.Base(repositoryRoot),
+		Branch:     branch,
+		BranchSlug: Slugify(branch),
+		User:       username,
+		Date:       time.Now().Format("2006-01-02"),
+	}
+}
+
+// ExecutePathTemplate renders a path_template after expanding a leading ~, $HOME or ${HOME}
+func ExecutePathTemplate(text string, data PathTemplateData) (string, error) {
+	tmpl, err := template.New("path_template").Parse(expandPath(text))
+	if err != nil {
+		return "", fmt.Errorf("invalid path_template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute path_template: %w", err)
+	}
+
+	path := strings.TrimSpace(buf.String())
+	if path == "" {
+		return "", fmt.Errorf("path_template %q produced an empty path", text)
+	}
+	return path, nil
+}
+
+var slugUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Slugify turns a branch name into a single path segment, e.g. feature/foo -> feature-foo
+func Slugify(branch string) string {
+	return strings.Trim(slugUnsafeChars.ReplaceAllString(branch, "-"), "-")
+}
+
+// expandPath expands a leading ~, $HOME or ${HOME} to the home directory. Other $ sequences
+// are left alone, since path templates use them for variables such as {{$b := .Branch}}.
+func expandPath(path string) string {
+	for _, prefix := range []string{"~", "${HOME}", "$HOME"} {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			continue
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		return home + rest
 	}
+	return path
 }
 
 // CopyFilesToWorktree copies configured files to the new worktree
@@ -39,11 +39,38 @@ func TestResolveWkitPath(t *testing.T) {
 			repoRoot:     "/repo",
 			expected:     "/absolute/path/feature-branch",
 		},
+		{
+			name:         "with relative path template",
+			config:       Config{WkitRoot: ".git/.wkit-worktrees", PathTemplate: "{{.BranchSlug}}"},
+			branch:       "feature/foo",
+			providedPath: "",
+			repoRoot:     "/repo",
+			expected:     "/repo/.git/.wkit-worktrees/feature-foo",
+		},
+		{
+			name:         "with absolute path template",
+			config:       Config{WkitRoot: ".git/.wkit-worktrees", PathTemplate: "/work/{{.Repo}}/{{.BranchSlug}}"},
+			branch:       "feature/foo",
+			providedPath: "",
+			repoRoot:     "/src/myrepo",
+			expected:     "/work/myrepo/feature-foo",
+		},
+		{
+			name:         "provided path wins over template",
+			config:       Config{WkitRoot: ".git/.wkit-worktrees", PathTemplate: "/work/{{.Branch}}"},
+			branch:       "feature/foo",
+			providedPath: "/custom/path",
+			repoRoot:     "/repo",
+			expected:     "/custom/path",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := tt.config.ResolveWkitPath(tt.branch, tt.providedPath, tt.repoRoot)
+			result, err := tt.config.ResolveWkitPath(tt.branch, tt.providedPath, tt.repoRoot)
+			if err != nil {
+				t.Fatalf("ResolveWkitPath() failed: %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("ResolveWkitPath() = %v, want %v", result, tt.expected)
 			}
@@ -58,12 +85,134 @@ func TestResolveWorktreePath_BackwardCompatibility(t *testing.T) {
 	repoRoot := "/repo"
 	expected := "/repo/.git/.wkit-worktrees/feature-branch"
 
-	result := config.ResolveWorktreePath(branch, "", repoRoot)
+	result, err := config.ResolveWorktreePath(branch, "", repoRoot)
+	if err != nil {
+		t.Fatalf("ResolveWorktreePath() failed: %v", err)
+	}
 	if result != expected {
 		t.Errorf("ResolveWorktreePath() = %v, want %v", result, expected)
 	}
 }
 
+func TestResolvePathTemplateRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"no template", "", ""},
+		{"branch in the last segment", "/work/{{.Repo}}/{{.BranchSlug}}", "/work/repo"},
+		{"branch in a middle segment", "/work/{{.BranchSlug}}/{{.Repo}}", "/work"},
+		{"branch joined with the repository", "/work/{{.Repo}}-{{.BranchSlug}}", "/work"},
+		{"relative template", "{{.BranchSlug}}", "/repo/.git/.wkit-worktrees"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{WkitRoot: ".git/.wkit-worktrees", PathTemplate: tt.template}
+			if result := config.ResolvePathTemplateRoot("/repo"); result != tt.expected {
+				t.Errorf("ResolvePathTemplateRoot() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecutePathTemplate(t *testing.T) {
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", "/home/tester")
+	defer os.Setenv("HOME", oldHome)
+
+	data := PathTemplateData{
+		Repo:       "wkit",
+		Branch:     "feature/foo",
+		BranchSlug: "feature-foo",
+		User:       "tester",
+		Date:       "2025-01-02",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		hasError bool
+	}{
+		{
+			name:     "tilde expansion",
+			template: "~/work/{{.Repo}}/{{.BranchSlug}}",
+			expected: "/home/tester/work/wkit/feature-foo",
+		},
+		{
+			name:     "HOME expansion",
+			template: "$HOME/wt/{{.User}}/{{.Date}}-{{.Branch}}",
+			expected: "/home/tester/wt/tester/2025-01-02-feature/foo",
+		},
+		{
+			name:     "braced HOME expansion",
+			template: "${HOME}/wt/{{.BranchSlug}}",
+			expected: "/home/tester/wt/feature-foo",
+		},
+		{
+			name:     "template variables are not environment variables",
+			template: "/wt/{{$b := .BranchSlug}}{{$b}}-{{$.Repo}}",
+			expected: "/wt/feature-foo-wkit",
+		},
+		{
+			name:     "other environment variables are kept",
+			template: "/wt/$WKIT_UNSET/{{.BranchSlug}}",
+			expected: "/wt/$WKIT_UNSET/feature-foo",
+		},
+		{
+			name:     "unknown field",
+			template: "{{.Unknown}}",
+			hasError: true,
+		},
+		{
+			name:     "syntax error",
+			template: "{{.Branch",
+			hasError: true,
+		},
+		{
+			name:     "empty result",
+			template: "{{if false}}x{{end}}",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExecutePathTemplate(tt.template, data)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("ExecutePathTemplate(%q) expected error, got %q", tt.template, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecutePathTemplate(%q) unexpected error: %v", tt.template, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ExecutePathTemplate(%q) = %q, want %q", tt.template, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"main":              "main",
+		"feature/foo":       "feature-foo",
+		"user/JIRA-1/fix":   "user-JIRA-1-fix",
+		"fix: spaces & co.": "fix-spaces-co.",
+		"/leading/":         "leading",
+	}
+
+	for input, expected := range tests {
+		if result := Slugify(input); result != expected {
+			t.Errorf("Slugify(%q) = %q, want %q", input, result, expected)
+		}
+	}
+}
+
 func TestLoad(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "wkit-test")
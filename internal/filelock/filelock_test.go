@@ -0,0 +1,66 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "state", "counter.lock")
+	counterPath := filepath.Join(dir, "state", "counter")
+
+	// Unlocked, concurrent increments would lose updates
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(lockPath)
+			if err != nil {
+				t.Errorf("Lock() failed: %v", err)
+				return
+			}
+			defer unlock()
+
+			data, _ := os.ReadFile(counterPath)
+			n, _ := strconv.Atoi(string(data))
+			if err := WriteFile(counterPath, []byte(strconv.Itoa(n+1)), 0o644); err != nil {
+				t.Errorf("WriteFile() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(counterPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "20" {
+		t.Errorf("Counter = %s after 20 locked increments, want 20", data)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("Lock file left behind: %v", err)
+	}
+}
+
+func TestLockTakesOverStaleLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "file.lock")
+	if err := os.WriteFile(lockPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * StaleAge)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := Lock(lockPath)
+	if err != nil {
+		t.Fatalf("Lock() with a stale lock failed: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,122 @@
+// Package fuzzy implements subsequence matching with scores, in the style of fzf
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scoring weights; consecutive characters and characters at word starts rank higher
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 24
+	bonusWordStart   = 16
+	bonusFirstChar   = 8
+	penaltyGap       = 1
+)
+
+// Match reports whether every character of pattern appears in text in order, ignoring case,
+// and scores the match. Positions holds the rune index in text of each matched character.
+// An empty pattern matches everything with a score of 0.
+func Match(pattern string, text string) (score int, positions []int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(t) {
+		// Lowercasing changed the length; fall back to the original runes
+		lower = t
+	}
+
+	// Find the rightmost start of the leftmost complete match, then score greedily from there.
+	// This prefers compact matches such as "wt" in "wkit-tool" matching "w" of "wkit" and "t" of "tool".
+	end := -1
+	pi := 0
+	for i := 0; i < len(lower) && pi < len(p); i++ {
+		if lower[i] == p[pi] {
+			pi++
+			if pi == len(p) {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	start := end
+	pi = len(p) - 1
+	for i := end; i >= 0 && pi >= 0; i-- {
+		if lower[i] == p[pi] {
+			start = i
+			pi--
+		}
+	}
+
+	positions = make([]int, 0, len(p))
+	pi = 0
+	prev := -1
+	for i := start; i <= end && pi < len(p); i++ {
+		if lower[i] != p[pi] {
+			continue
+		}
+		score += scoreMatch
+		if i == 0 {
+			score += bonusFirstChar
+		}
+		if isWordStart(t, i) {
+			score += bonusWordStart
+		}
+		if prev >= 0 {
+			if i == prev+1 {
+				score += bonusConsecutive
+			} else {
+				score -= penaltyGap * (i - prev - 1)
+			}
+		}
+		positions = append(positions, i)
+		prev = i
+		pi++
+	}
+	return score, positions, true
+}
+
+// isWordStart reports whether the rune at i starts a word: it follows a separator
+// or is an upper-case letter after a lower-case one
+func isWordStart(t []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := t[i-1]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(t[i]) && unicode.IsLower(prev)
+}
+
+// Result is an item that matched a pattern
+type Result struct {
+	Index     int // index of the item in the slice given to Filter
+	Score     int
+	Positions []int
+}
+
+// Filter returns the items matching pattern, best first. Items with equal scores keep
+// their order, and shorter items win ties so that "api" ranks above "api-v2".
+func Filter(pattern string, items []string) []Result {
+	var results []Result
+	for i, item := range items {
+		if score, positions, ok := Match(pattern, item); ok {
+			results = append(results, Result{Index: i, Score: score, Positions: positions})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return len(items[results[i].Index]) < len(items[results[j].Index])
+	})
+	return results
+}
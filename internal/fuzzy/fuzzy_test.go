@@ -0,0 +1,68 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{pattern: "", text: "anything", ok: true},
+		{pattern: "fb", text: "feature/bar", ok: true, positions: []int{0, 8}},
+		{pattern: "FB", text: "feature/bar", ok: true, positions: []int{0, 8}},
+		{pattern: "bar", text: "feature/bar", ok: true, positions: []int{8, 9, 10}},
+		{pattern: "abc", text: "acb", ok: false},
+		{pattern: "xyz", text: "feature/bar", ok: false},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := Match(tt.pattern, tt.text)
+		if ok != tt.ok {
+			t.Errorf("Match(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("Match(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.positions)
+		}
+	}
+}
+
+func TestMatchScores(t *testing.T) {
+	better := []struct{ pattern, better, worse string }{
+		{pattern: "api", better: "api", worse: "a-p-i"},
+		{pattern: "fb", better: "feature/bar", worse: "fooba"},
+		{pattern: "log", better: "fix/login", worse: "flog-x"},
+	}
+
+	for _, tt := range better {
+		betterScore, _, _ := Match(tt.pattern, tt.better)
+		worseScore, _, _ := Match(tt.pattern, tt.worse)
+		if betterScore <= worseScore {
+			t.Errorf("Match(%q): %q scored %d, not above %q with %d", tt.pattern, tt.better, betterScore, tt.worse, worseScore)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	items := []string{"api-v2", "main", "api", "docs/api-guide"}
+
+	results := Filter("api", items)
+	var matched []string
+	for _, result := range results {
+		matched = append(matched, items[result.Index])
+	}
+
+	expected := []string{"api", "api-v2", "docs/api-guide"}
+	if !reflect.DeepEqual(matched, expected) {
+		t.Errorf("Filter() = %v, want %v", matched, expected)
+	}
+
+	if results := Filter("", items); len(results) != len(items) {
+		t.Errorf("Filter(\"\") returned %d items, want %d", len(results), len(items))
+	}
+}
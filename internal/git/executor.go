@@ -87,9 +87,9 @@ func (e *Executor) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
-// Status returns git status output in porcelain format
+// Status returns git status output in porcelain v2 format with branch headers
 func (e *Executor) Status() (string, error) {
-	return e.Execute("status", "--porcelain", "--ahead-behind")
+	return e.Execute("status", "--porcelain=v2", "--branch")
 }
 
 // BranchExists checks if a local branch exists
@@ -0,0 +1,230 @@
+// Package index keeps a global, frecency-ranked index of the worktrees visited with wkit,
+// in the style of zoxide, so worktrees of every repository can be found from anywhere
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"wkit/internal/filelock"
+	"wkit/internal/fuzzy"
+)
+
+// maxTotalRank is the total rank above which every entry is aged, so old entries fade
+// out and entries below a rank of 1 are dropped
+const maxTotalRank = 1000
+
+// Entry is a worktree in the index
+type Entry struct {
+	Path       string    `json:"path" yaml:"path"`
+	Repo       string    `json:"repo" yaml:"repo"`     // root of the repository the worktree belongs to
+	Branch     string    `json:"branch" yaml:"branch"` // empty for detached worktrees
+	Rank       float64   `json:"rank" yaml:"rank"`
+	LastAccess time.Time `json:"last_access" yaml:"last_access"`
+}
+
+// Label is the text queries are matched against: "<repository name>/<branch>", or the
+// directory name of detached worktrees
+func (e Entry) Label() string {
+	name := e.Branch
+	if name == "" {
+		name = filepath.Base(e.Path)
+	}
+	return filepath.Base(e.Repo) + "/" + name
+}
+
+// Frecency scores the entry by how often and how recently it was visited
+func (e Entry) Frecency(now time.Time) float64 {
+	age := now.Sub(e.LastAccess)
+	switch {
+	case age < time.Hour:
+		return e.Rank * 4
+	case age < 24*time.Hour:
+		return e.Rank * 2
+	case age < 7*24*time.Hour:
+		return e.Rank / 2
+	}
+	return e.Rank / 4
+}
+
+// Index is the set of indexed worktrees
+type Index struct {
+	Entries []Entry `json:"entries"`
+}
+
+// DefaultPath returns the index file in the XDG data directory
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "wkit", "index.json"), nil
+}
+
+// Load reads the index at path; a missing file is an empty index
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Index{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", path, err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index to path, replacing the file atomically so readers never see a
+// partial index. It does not stop two processes from overwriting each other's changes;
+// use Update to modify the index on disk.
+func (idx *Index) Save(path string) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	if err := filelock.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// Update loads the index at path, applies update and saves the result, holding a lock file
+// next to the index so that concurrent wkit processes do not lose each other's visits.
+// Nothing is saved when update returns an error.
+func Update(path string, update func(idx *Index) error) error {
+	unlock, err := filelock.Lock(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock index: %w", err)
+	}
+	defer unlock()
+
+	idx, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if err := update(idx); err != nil {
+		return err
+	}
+	return idx.Save(path)
+}
+
+// Visit records a visit to the worktree at path, adding it when it is not indexed yet
+func (idx *Index) Visit(path string, repo string, branch string, now time.Time) {
+	found := false
+	for i := range idx.Entries {
+		entry := &idx.Entries[i]
+		if entry.Path == path {
+			entry.Repo = repo
+			entry.Branch = branch
+			entry.Rank++
+			entry.LastAccess = now
+			found = true
+			break
+		}
+	}
+	if !found {
+		idx.Entries = append(idx.Entries, Entry{Path: path, Repo: repo, Branch: branch, Rank: 1, LastAccess: now})
+	}
+	idx.age()
+}
+
+// Remove drops the worktree at path from the index
+func (idx *Index) Remove(path string) {
+	kept := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		if entry.Path != path {
+			kept = append(kept, entry)
+		}
+	}
+	idx.Entries = kept
+}
+
+// age scales every rank down once the total exceeds maxTotalRank, dropping entries that
+// fall below a rank of 1
+func (idx *Index) age() {
+	var total float64
+	for _, entry := range idx.Entries {
+		total += entry.Rank
+	}
+	if total <= maxTotalRank {
+		return
+	}
+
+	factor := 0.9 * maxTotalRank / total
+	kept := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		entry.Rank *= factor
+		if entry.Rank >= 1 {
+			kept = append(kept, entry)
+		}
+	}
+	idx.Entries = kept
+}
+
+// Match is an entry that matched a query
+type Match struct {
+	Entry
+	Score float64 `json:"score" yaml:"score"` // frecency of the entry
+}
+
+// Query returns the entries whose label fuzzy-matches every keyword, highest frecency first.
+// Matches much weaker than the best one, such as scattered characters when another label
+// contains the keyword as a word, are left out so frecency does not outrank a clear match.
+func (idx *Index) Query(keywords []string, now time.Time) []Match {
+	type candidate struct {
+		match      Match
+		fuzzyScore int
+	}
+
+	var candidates []candidate
+	best := 0
+	for _, entry := range idx.Entries {
+		total, ok := 0, true
+		for _, keyword := range keywords {
+			score, _, matched := fuzzy.Match(keyword, entry.Label())
+			if !matched {
+				ok = false
+				break
+			}
+			total += score
+		}
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{match: Match{Entry: entry, Score: entry.Frecency(now)}, fuzzyScore: total})
+		if total > best {
+			best = total
+		}
+	}
+
+	var matches []Match
+	for _, c := range candidates {
+		if c.fuzzyScore*2 >= best {
+			matches = append(matches, c.match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return strings.Compare(matches[i].Path, matches[j].Path) < 0
+	})
+	return matches
+}
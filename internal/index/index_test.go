@@ -0,0 +1,173 @@
+package index
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVisitAndRemove(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	idx := &Index{}
+
+	idx.Visit("/wt/a", "/repo", "a", now)
+	idx.Visit("/wt/b", "/repo", "b", now)
+	idx.Visit("/wt/a", "/repo", "a", now.Add(time.Minute))
+
+	expected := []Entry{
+		{Path: "/wt/a", Repo: "/repo", Branch: "a", Rank: 2, LastAccess: now.Add(time.Minute)},
+		{Path: "/wt/b", Repo: "/repo", Branch: "b", Rank: 1, LastAccess: now},
+	}
+	if !reflect.DeepEqual(idx.Entries, expected) {
+		t.Errorf("Entries = %v, want %v", idx.Entries, expected)
+	}
+
+	idx.Remove("/wt/a")
+	if len(idx.Entries) != 1 || idx.Entries[0].Path != "/wt/b" {
+		t.Errorf("Entries after Remove = %v, want only /wt/b", idx.Entries)
+	}
+}
+
+func TestAging(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	idx := &Index{Entries: []Entry{
+		{Path: "/wt/busy", Rank: maxTotalRank, LastAccess: now},
+		{Path: "/wt/rare", Rank: 1, LastAccess: now},
+	}}
+
+	idx.Visit("/wt/busy", "/repo", "busy", now)
+
+	if len(idx.Entries) != 1 || idx.Entries[0].Path != "/wt/busy" {
+		t.Fatalf("Entries = %v, want only /wt/busy", idx.Entries)
+	}
+	if rank := idx.Entries[0].Rank; rank > maxTotalRank {
+		t.Errorf("Rank = %v, want at most %v", rank, maxTotalRank)
+	}
+}
+
+func TestFrecency(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		age      time.Duration
+		expected float64
+	}{
+		{time.Minute, 40},
+		{3 * time.Hour, 20},
+		{3 * 24 * time.Hour, 5},
+		{30 * 24 * time.Hour, 2.5},
+	}
+
+	for _, tt := range tests {
+		entry := Entry{Rank: 10, LastAccess: now.Add(-tt.age)}
+		if got := entry.Frecency(now); got != tt.expected {
+			t.Errorf("Frecency() with age %s = %v, want %v", tt.age, got, tt.expected)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	idx := &Index{Entries: []Entry{
+		{Path: "/src/api/.git/.wkit-worktrees/feature/login", Repo: "/src/api", Branch: "feature/login", Rank: 2, LastAccess: now},
+		{Path: "/src/web/.git/.wkit-worktrees/feature/login", Repo: "/src/web", Branch: "feature/login", Rank: 5, LastAccess: now},
+		{Path: "/src/web", Repo: "/src/web", Branch: "main", Rank: 20, LastAccess: now},
+		{Path: "/src/web/detached", Repo: "/src/web", Rank: 1, LastAccess: now},
+	}}
+
+	tests := []struct {
+		name     string
+		keywords []string
+		expected []string
+	}{
+		{"frecency order", []string{"login"}, []string{"/src/web/.git/.wkit-worktrees/feature/login", "/src/api/.git/.wkit-worktrees/feature/login"}},
+		{"every keyword must match", []string{"api", "login"}, []string{"/src/api/.git/.wkit-worktrees/feature/login"}},
+		{"detached worktrees match their directory", []string{"detached"}, []string{"/src/web/detached"}},
+		{"no match", []string{"zzz"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			for _, match := range idx.Query(tt.keywords, now) {
+				paths = append(paths, match.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Errorf("Query(%v) = %v, want %v", tt.keywords, paths, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wkit", "index.json")
+
+	idx, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file failed: %v", err)
+	}
+	if len(idx.Entries) != 0 {
+		t.Errorf("Load() of a missing file = %v, want empty", idx.Entries)
+	}
+
+	now := time.Unix(1700000000, 0).UTC()
+	idx.Visit("/wt/a", "/repo", "a", now)
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Entries, idx.Entries) {
+		t.Errorf("Load() = %v, want %v", loaded.Entries, idx.Entries)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/data/wkit/index.json" {
+		t.Errorf("DefaultPath() = %s, want /data/wkit/index.json", path)
+	}
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wkit", "index.json")
+	now := time.Unix(1700000000, 0).UTC()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := Update(path, func(idx *Index) error {
+				idx.Visit(fmt.Sprintf("/wt/%d", i), "/repo", "", now)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update() failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	idx, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(idx.Entries) != 20 {
+		t.Errorf("Index has %d entries after 20 concurrent updates, want 20", len(idx.Entries))
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("Lock file left behind: %v", err)
+	}
+
+}
@@ -0,0 +1,190 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats supported by Write in addition to Go templates
+const (
+	Table  = "table"
+	JSON   = "json"
+	YAML   = "yaml"
+	TSV    = "tsv"
+	NDJSON = "ndjson"
+)
+
+// FlagUsage is the help text shared by every --format flag
+const FlagUsage = "Output format (table, json, yaml, tsv, ndjson, or a Go template)"
+
+// IsTable reports whether format selects the human-readable output
+func IsTable(format string) bool {
+	return format == "" || format == Table
+}
+
+// Validate checks that format is a known format or a valid Go template
+func Validate(format string) error {
+	switch format {
+	case "", Table, JSON, YAML, TSV, NDJSON:
+		return nil
+	}
+
+	if !strings.Contains(format, "{{") {
+		return fmt.Errorf("unknown format: %s. Valid values: table, json, yaml, tsv, ndjson, or a Go template", format)
+	}
+	if _, err := template.New("format").Parse(format); err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+	return nil
+}
+
+// Write renders data, a struct or a slice of structs, in the given format.
+// The table function is called for the human-readable format.
+func Write(w io.Writer, format string, data any, table func(io.Writer) error) error {
+	if err := Validate(format); err != nil {
+		return err
+	}
+
+	switch format {
+	case "", Table:
+		return table(w)
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case YAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(data)
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		for _, item := range items(data) {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TSV:
+		return writeTSV(w, items(data))
+	}
+
+	tmpl := template.Must(template.New("format").Parse(format))
+	for _, item := range items(data) {
+		if err := tmpl.Execute(w, item); err != nil {
+			return fmt.Errorf("failed to execute format template: %w", err)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// items returns the elements of a slice, or data itself for a single value
+func items(data any) []any {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []any{data}
+	}
+
+	result := make([]any, v.Len())
+	for i := range result {
+		result[i] = v.Index(i).Interface()
+	}
+	return result
+}
+
+// writeTSV writes a header row of field names followed by one row per item
+func writeTSV(w io.Writer, items []any) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var header []string
+	for i, item := range items {
+		var names, values []string
+		flatten(reflect.ValueOf(item), "", &names, &values)
+		if i == 0 {
+			header = names
+			if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten collects the json field names and values of a struct, prefixing nested structs with "<name>."
+func flatten(v reflect.Value, prefix string, names *[]string, values *[]string) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			*names = append(*names, strings.TrimSuffix(prefix, "."))
+			*values = append(*values, "")
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		*names = append(*names, strings.TrimSuffix(prefix, "."))
+		*values = append(*values, formatValue(v))
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		// Fields of embedded structs are promoted, like encoding/json does
+		if field.Anonymous && name == "" {
+			flatten(v.Field(i), prefix, names, values)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldValue := v.Field(i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().PkgPath() != "time" {
+			flatten(fieldValue, prefix+name+".", names, values)
+			continue
+		}
+		*names = append(*names, prefix+name)
+		*values = append(*values, formatValue(fieldValue))
+	}
+}
+
+// formatValue formats a single TSV cell; slices are joined with commas
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	// Tabs and newlines would break the row structure
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(fmt.Sprint(v.Interface()))
+}
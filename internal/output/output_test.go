@@ -0,0 +1,148 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type testEmbedded struct {
+	Ahead int `json:"ahead" yaml:"ahead"`
+}
+
+type testRecord struct {
+	Name   string   `json:"name" yaml:"name"`
+	Files  []string `json:"files" yaml:"files"`
+	Nested struct {
+		Enabled bool `json:"enabled" yaml:"enabled"`
+	} `json:"nested" yaml:"nested"`
+	testEmbedded `yaml:",inline"`
+	Error        string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func testRecords() []testRecord {
+	first := testRecord{Name: "main", Files: []string{"a", "b"}}
+	first.Nested.Enabled = true
+	first.Ahead = 2
+	second := testRecord{Name: "feature\tx", Error: "boom"}
+	return []testRecord{first, second}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		data     any
+		expected string
+	}{
+		{
+			name:     "table",
+			format:   "",
+			data:     testRecords(),
+			expected: "table output\n",
+		},
+		{
+			name:   "json",
+			format: JSON,
+			data:   testRecords()[:1],
+			expected: `[
+  {
+    "name": "main",
+    "files": [
+      "a",
+      "b"
+    ],
+    "nested": {
+      "enabled": true
+    },
+    "ahead": 2
+  }
+]
+`,
+		},
+		{
+			name:   "yaml",
+			format: YAML,
+			data:   testRecords()[0],
+			expected: `name: main
+files:
+  - a
+  - b
+nested:
+  enabled: true
+ahead: 2
+`,
+		},
+		{
+			name:   "ndjson",
+			format: NDJSON,
+			data:   testRecords(),
+			expected: `{"name":"main","files":["a","b"],"nested":{"enabled":true},"ahead":2}
+{"name":"feature\tx","files":null,"nested":{"enabled":false},"ahead":0,"error":"boom"}
+`,
+		},
+		{
+			name:   "tsv",
+			format: TSV,
+			data:   testRecords(),
+			expected: "name\tfiles\tnested.enabled\tahead\terror\n" +
+				"main\ta,b\ttrue\t2\t\n" +
+				"feature x\t\tfalse\t0\tboom\n",
+		},
+		{
+			name:     "template over slice",
+			format:   "{{.Name}} {{.Ahead}}",
+			data:     testRecords(),
+			expected: "main 2\nfeature\tx 0\n",
+		},
+		{
+			name:     "template over single value",
+			format:   "{{.Name}}",
+			data:     testRecords()[0],
+			expected: "main\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := Write(buf, tt.format, tt.data, func(w io.Writer) error {
+				_, err := io.WriteString(w, "table output\n")
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Write() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		format   string
+		hasError bool
+	}{
+		{format: "", hasError: false},
+		{format: "table", hasError: false},
+		{format: "json", hasError: false},
+		{format: "yaml", hasError: false},
+		{format: "tsv", hasError: false},
+		{format: "ndjson", hasError: false},
+		{format: "{{.Branch}}", hasError: false},
+		{format: "xml", hasError: true},
+		{format: "{{.Branch", hasError: true},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.format)
+		if tt.hasError && err == nil {
+			t.Errorf("Validate(%q) expected error, got nil", tt.format)
+		}
+		if !tt.hasError && err != nil {
+			t.Errorf("Validate(%q) unexpected error: %v", tt.format, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Key is a key press read from a terminal in raw mode
+type Key struct {
+	Rune rune   // printable character, or 0 for special keys
+	Name string // name of a special key: up, down, enter, escape, backspace, ctrl-c
+}
+
+// Special keys
+var (
+	KeyUp        = Key{Name: "up"}
+	KeyDown      = Key{Name: "down"}
+	KeyEnter     = Key{Name: "enter"}
+	KeyEscape    = Key{Name: "escape"}
+	KeyBackspace = Key{Name: "backspace"}
+	KeyCtrlC     = Key{Name: "ctrl-c"}
+)
+
+// MakeRaw puts the terminal f into raw mode and returns a function that restores it
+func MakeRaw(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = term.Restore(fd, state) }, nil
+}
+
+// ReadKey reads one key press from r, which should be a terminal in raw mode
+func ReadKey(r io.Reader) (Key, error) {
+	buf := make([]byte, 16)
+	n, err := r.Read(buf)
+	if err != nil {
+		return Key{}, err
+	}
+	return ParseKey(buf[:n]), nil
+}
+
+// ParseKey decodes the bytes of a single key press
+func ParseKey(b []byte) Key {
+	if len(b) == 0 {
+		return Key{}
+	}
+
+	switch string(b) {
+	case "\x1b[A", "\x1bOA":
+		return KeyUp
+	case "\x1b[B", "\x1bOB":
+		return KeyDown
+	case "\x1b":
+		return KeyEscape
+	}
+
+	switch b[0] {
+	case '\r', '\n':
+		return KeyEnter
+	case 0x7f, 0x08:
+		return KeyBackspace
+	case 0x03:
+		return KeyCtrlC
+	case 0x0e: // Ctrl-N
+		return KeyDown
+	case 0x10: // Ctrl-P
+		return KeyUp
+	}
+
+	if b[0] < 0x20 || b[0] == 0x1b {
+		return Key{Name: "unknown"}
+	}
+	runes := []rune(string(b))
+	return Key{Rune: runes[0]}
+}
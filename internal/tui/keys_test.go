@@ -0,0 +1,27 @@
+package tui
+
+import "testing"
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Key
+	}{
+		{input: "j", expected: Key{Rune: 'j'}},
+		{input: "é", expected: Key{Rune: 'é'}},
+		{input: "\x1b[A", expected: KeyUp},
+		{input: "\x1bOB", expected: KeyDown},
+		{input: "\x0e", expected: KeyDown},
+		{input: "\r", expected: KeyEnter},
+		{input: "\x7f", expected: KeyBackspace},
+		{input: "\x03", expected: KeyCtrlC},
+		{input: "\x1b", expected: KeyEscape},
+		{input: "\x1b[5~", expected: Key{Name: "unknown"}},
+	}
+
+	for _, tt := range tests {
+		if result := ParseKey([]byte(tt.input)); result != tt.expected {
+			t.Errorf("ParseKey(%q) = %+v, want %+v", tt.input, result, tt.expected)
+		}
+	}
+}
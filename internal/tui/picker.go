@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"wkit/internal/fuzzy"
+)
+
+// ErrCancelled is returned by Pick when the user quits without choosing
+var ErrCancelled = errors.New("cancelled")
+
+// Picker is a fuzzy-filterable list drawn on the controlling terminal, so that it works
+// even when stdout is captured, e.g. by cd "$(wkit switch)"
+type Picker struct {
+	Prompt string
+	Items  []string
+	// Preview returns details of an item, shown below the list; it may be nil
+	Preview func(index int) string
+}
+
+// CanPick reports whether a picker can be shown, that is whether wkit runs interactively
+func CanPick() bool {
+	if !IsTerminal(os.Stdin) {
+		return false
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	tty.Close()
+	return true
+}
+
+// Pick shows the picker and returns the index of the chosen item
+func (p *Picker) Pick() (int, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return -1, fmt.Errorf("failed to open terminal: %w", err)
+	}
+	defer tty.Close()
+
+	restore, err := MakeRaw(tty)
+	if err != nil {
+		return -1, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer restore()
+
+	screen := NewScreen(tty)
+	defer screen.Close()
+
+	previews := make(map[int]string)
+	var query []rune
+	selected := 0
+	for {
+		results := fuzzy.Filter(string(query), p.Items)
+		if selected >= len(results) {
+			selected = len(results) - 1
+		}
+		if selected < 0 {
+			selected = 0
+		}
+
+		width, height := Size(tty)
+		screen.Draw(p.render(string(query), results, selected, previews, width, height))
+
+		key, err := ReadKey(tty)
+		if err != nil {
+			return -1, err
+		}
+		switch {
+		case key == KeyCtrlC || key == KeyEscape:
+			return -1, ErrCancelled
+		case key == KeyEnter:
+			if len(results) == 0 {
+				continue
+			}
+			return results[selected].Index, nil
+		case key == KeyUp:
+			selected--
+		case key == KeyDown:
+			selected++
+		case key == KeyBackspace:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				selected = 0
+			}
+		case key.Rune != 0:
+			query = append(query, key.Rune)
+			selected = 0
+		}
+	}
+}
+
+// render draws the prompt, the matching items and the preview of the selected one
+func (p *Picker) render(query string, results []fuzzy.Result, selected int, previews map[int]string, width int, height int) string {
+	var preview []string
+	if p.Preview != nil && len(results) > 0 {
+		index := results[selected].Index
+		if _, ok := previews[index]; !ok {
+			previews[index] = p.Preview(index)
+		}
+		preview = strings.Split(strings.TrimRight(previews[index], "\n"), "\n")
+	}
+
+	// Prompt and counter take 2 lines; the preview gets a separator line and at most half the screen
+	if len(preview) > height/2 {
+		preview = preview[:height/2]
+	}
+	visible := height - 2
+	if len(preview) > 0 {
+		visible -= len(preview) + 1
+	}
+	if visible < 1 {
+		visible = 1
+	}
+	first := 0
+	if selected >= visible {
+		first = selected - visible + 1
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s\x1b[0m\n", p.Prompt, query)
+	fmt.Fprintf(&b, "  %d/%d\n", len(results), len(p.Items))
+	for i := first; i < len(results) && i < first+visible; i++ {
+		line := highlightMatches(Truncate(p.Items[results[i].Index], width-2), results[i].Positions)
+		if i == selected {
+			fmt.Fprintf(&b, "\x1b[7m> %s\x1b[0m\n", line)
+		} else {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	if len(preview) > 0 {
+		fmt.Fprintln(&b, strings.Repeat("─", width))
+		for _, line := range preview {
+			fmt.Fprintln(&b, Truncate(line, width))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// highlightMatches underlines the runes of s at positions
+func highlightMatches(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		if matched[i] {
+			b.WriteString("\x1b[4m" + string(r) + "\x1b[24m")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
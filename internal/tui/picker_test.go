@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"wkit/internal/fuzzy"
+)
+
+func TestPickerRender(t *testing.T) {
+	picker := &Picker{
+		Prompt:  "> ",
+		Items:   []string{"main", "feature/api", "feature/api-v2"},
+		Preview: func(index int) string { return "preview " + []string{"main", "api", "api-v2"}[index] },
+	}
+	results := fuzzy.Filter("api", picker.Items)
+
+	output := picker.render("api", results, 1, map[int]string{}, 80, 24)
+	lines := strings.Split(output, "\n")
+
+	if lines[0] != "> api\x1b[0m" {
+		t.Errorf("prompt line = %q", lines[0])
+	}
+	if lines[1] != "  2/3" {
+		t.Errorf("counter line = %q, want %q", lines[1], "  2/3")
+	}
+	if !strings.HasPrefix(lines[3], "\x1b[7m> ") || !strings.Contains(lines[3], "-v2") {
+		t.Errorf("selected line = %q, want feature/api-v2 highlighted", lines[3])
+	}
+	if lines[len(lines)-1] != "preview api-v2" {
+		t.Errorf("preview line = %q, want %q", lines[len(lines)-1], "preview api-v2")
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	if result := highlightMatches("abc", []int{1}); result != "a\x1b[4mb\x1b[24mc" {
+		t.Errorf("highlightMatches() = %q", result)
+	}
+	if result := highlightMatches("abc", nil); result != "abc" {
+		t.Errorf("highlightMatches() = %q, want %q", result, "abc")
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences used to drive the terminal
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// Screen draws full-screen content on a terminal, restoring it on Close
+type Screen struct {
+	out io.Writer
+}
+
+// IsTerminal reports whether f is connected to a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// NewScreen switches out to the alternate screen and hides the cursor
+func NewScreen(out io.Writer) *Screen {
+	fmt.Fprint(out, enterAltScreen+hideCursor)
+	return &Screen{out: out}
+}
+
+// Draw replaces everything on the screen with content.
+// Line feeds are written as CRLF so that drawing also works in raw mode.
+func (s *Screen) Draw(content string) {
+	content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	fmt.Fprint(s.out, clearScreen+content)
+}
+
+// Close shows the cursor and switches back to the normal screen
+func (s *Screen) Close() {
+	fmt.Fprint(s.out, showCursor+exitAltScreen)
+}
+
+// Size returns the width and height of the terminal f, falling back to 80x24
+func Size(f *os.File) (int, int) {
+	width, height, err := term.GetSize(int(f.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Truncate shortens s to at most width runes
+func Truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width])
+}
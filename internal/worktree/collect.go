@@ -0,0 +1,69 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrStatusTimeout is returned for worktrees whose status did not finish in time
+var ErrStatusTimeout = errors.New("status timed out")
+
+// StatusResult holds the status of a single worktree collected by CollectStatuses
+type StatusResult struct {
+	Worktree Worktree
+	Status   *WorktreeStatus
+	Err      error
+}
+
+// CollectStatuses gets the status of every worktree concurrently, including how far it has
+// diverged from the remote-tracking mainBranch (skipped when mainBranch is empty).
+// At most parallelism statuses run at once (one per CPU when it is 0 or less), and each
+// one is cancelled after timeout (no limit when it is 0). Results keep the order of worktrees.
+func (m *Manager) CollectStatuses(ctx context.Context, worktrees []Worktree, mainBranch string, parallelism int, timeout time.Duration) []StatusResult {
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	results := make([]StatusResult, len(worktrees))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, wt := range worktrees {
+		results[i].Worktree = wt
+		wg.Add(1)
+		go func(i int, wt Worktree) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].Status, results[i].Err = m.getWorktreeStatusWithTimeout(ctx, wt.Path, mainBranch, timeout)
+		}(i, wt)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (m *Manager) getWorktreeStatusWithTimeout(ctx context.Context, worktreePath string, mainBranch string, timeout time.Duration) (*WorktreeStatus, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	status, err := m.GetWorktreeStatusContext(ctx, worktreePath)
+	if err == nil && mainBranch != "" {
+		status.MainRef, status.MainAhead, status.MainBehind, err = m.GetMainDivergenceContext(ctx, worktreePath, mainBranch)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s", ErrStatusTimeout, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,138 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollectStatuses(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping TestCollectStatuses: git not available")
+	}
+
+	var worktrees []Worktree
+	for _, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(t.TempDir(), name)
+		if output, err := exec.Command("git", "init", "-q", path).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed: %v: %s", err, output)
+		}
+		worktrees = append(worktrees, Worktree{Path: path, Branch: name})
+	}
+	// A worktree whose directory is gone must not hide the others
+	worktrees = append(worktrees, Worktree{Path: filepath.Join(t.TempDir(), "missing"), Branch: "missing"})
+
+	manager, _ := NewManager()
+	results := manager.CollectStatuses(context.Background(), worktrees, "main", 2, 10*time.Second)
+
+	if len(results) != len(worktrees) {
+		t.Fatalf("Expected %d results, got %d", len(worktrees), len(results))
+	}
+	for i, result := range results {
+		if result.Worktree.Branch != worktrees[i].Branch {
+			t.Errorf("results[%d] is %s, want %s", i, result.Worktree.Branch, worktrees[i].Branch)
+		}
+	}
+	for _, result := range results[:3] {
+		if result.Err != nil {
+			t.Errorf("Unexpected error for %s: %v", result.Worktree.Branch, result.Err)
+		} else if !result.Status.IsClean {
+			t.Errorf("Expected %s to be clean", result.Worktree.Branch)
+		}
+	}
+	if results[3].Err == nil {
+		t.Error("Expected an error for the missing worktree")
+	}
+}
+
+func TestCollectStatusesTimeout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping TestCollectStatusesTimeout: git not available")
+	}
+
+	path := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", path).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, output)
+	}
+
+	manager, _ := NewManager()
+	results := manager.CollectStatuses(context.Background(), []Worktree{{Path: path}}, "", 1, time.Nanosecond)
+
+	if !errors.Is(results[0].Err, ErrStatusTimeout) {
+		t.Errorf("Expected ErrStatusTimeout, got %v", results[0].Err)
+	}
+}
+
+func TestGetMainDivergenceContext(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping TestGetMainDivergenceContext: git not available")
+	}
+
+	path := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = path
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "base")
+
+	manager, _ := NewManager()
+	ref, _, _, err := manager.GetMainDivergenceContext(context.Background(), path, "main")
+	if err != nil || ref != "" {
+		t.Fatalf("Expected no ref without a remote-tracking branch, got %q, %v", ref, err)
+	}
+
+	git("update-ref", "refs/remotes/origin/main", "HEAD")
+	git("commit", "-q", "--allow-empty", "-m", "feature 1")
+	git("commit", "-q", "--allow-empty", "-m", "feature 2")
+
+	ref, ahead, behind, err := manager.GetMainDivergenceContext(context.Background(), path, "main")
+	if err != nil {
+		t.Fatalf("GetMainDivergenceContext() failed: %v", err)
+	}
+	if ref != "origin/main" || ahead != 2 || behind != 0 {
+		t.Errorf("GetMainDivergenceContext() = %q, %d, %d, want origin/main, 2, 0", ref, ahead, behind)
+	}
+}
+
+func TestGetWorktreeStatusIncludeIgnored(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping TestGetWorktreeStatusIncludeIgnored: git not available")
+	}
+
+	path := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", path).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, output)
+	}
+	for name, content := range map[string]string{".gitignore": "*.log\n", "debug.log": "noise\n"} {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager, _ := NewManager()
+	status, err := manager.GetWorktreeStatus(path)
+	if err != nil {
+		t.Fatalf("GetWorktreeStatus() failed: %v", err)
+	}
+	if status.Ignored != 0 {
+		t.Errorf("Ignored files counted by default: %d", status.Ignored)
+	}
+
+	manager.IncludeIgnored = true
+	status, err = manager.GetWorktreeStatus(path)
+	if err != nil {
+		t.Fatalf("GetWorktreeStatus() failed: %v", err)
+	}
+	if status.Ignored != 1 {
+		t.Errorf("Ignored = %d with IncludeIgnored, want 1", status.Ignored)
+	}
+}
@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotInWorktree is returned when the current directory is not inside a worktree
+var ErrNotInWorktree = errors.New("current directory is not inside a worktree")
+
+// CurrentWorktreePath returns the top level of the worktree containing the current directory,
+// with symlinks resolved, so it works from subdirectories and symlinked paths alike
+func CurrentWorktreePath() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		// Outside a repository, or inside a .git directory
+		return "", ErrNotInWorktree
+	}
+
+	toplevel := strings.TrimSpace(string(output))
+	if toplevel == "" {
+		return "", ErrNotInWorktree
+	}
+	return resolveSymlinks(toplevel), nil
+}
+
+// CurrentWorktree returns the worktree containing the current directory
+func (m *Manager) CurrentWorktree() (*Worktree, error) {
+	toplevel, err := CurrentWorktreePath()
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	wt := findWorktreeByResolvedPath(toplevel, worktrees)
+	if wt == nil {
+		return nil, ErrNotInWorktree
+	}
+	return wt, nil
+}
+
+// findWorktreeByResolvedPath returns the worktree whose path is path once symlinks are resolved
+func findWorktreeByResolvedPath(path string, worktrees []Worktree) *Worktree {
+	for i := range worktrees {
+		if worktrees[i].Path == path || resolveSymlinks(worktrees[i].Path) == path {
+			return &worktrees[i]
+		}
+	}
+	return nil
+}
+
+// SamePath reports whether a and b are the same path once symlinks are resolved
+func SamePath(a string, b string) bool {
+	return a == b || resolveSymlinks(a) == resolveSymlinks(b)
+}
+
+// resolveSymlinks returns path with symlinks resolved, or path itself when that fails
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindWorktreeByResolvedPath(t *testing.T) {
+	dir := resolveSymlinks(t.TempDir())
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := os.MkdirAll(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("Skipping: symlinks not supported: %v", err)
+	}
+
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: link, Branch: "feature"}, // registered through a symlink
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/repo", "main"},
+		{real, "feature"},
+		{filepath.Join(dir, "other"), ""},
+	}
+
+	for _, tt := range tests {
+		wt := findWorktreeByResolvedPath(tt.path, worktrees)
+		got := ""
+		if wt != nil {
+			got = wt.Branch
+		}
+		if got != tt.expected {
+			t.Errorf("findWorktreeByResolvedPath(%s) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	dir := resolveSymlinks(t.TempDir())
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := os.MkdirAll(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("Skipping: symlinks not supported: %v", err)
+	}
+
+	if !SamePath(real, link) || !SamePath(link, real) || !SamePath(real, real) {
+		t.Error("SamePath() does not see through the symlink")
+	}
+	if SamePath(real, dir) {
+		t.Error("SamePath() matched different directories")
+	}
+}
@@ -0,0 +1,165 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wkit/internal/filelock"
+)
+
+// historyFileName is the switch history kept in the git common dir, so it is shared by
+// every worktree of a repository
+const historyFileName = "wkit-history"
+
+// historyLimit is the number of visits kept in the history file
+const historyLimit = 200
+
+// RecentWorktree is a worktree from the switch history
+type RecentWorktree struct {
+	Worktree Worktree
+	Visited  time.Time
+}
+
+// historyEntry is a line of the history file
+type historyEntry struct {
+	Path    string
+	Visited time.Time
+}
+
+// RecordSwitch records a switch from the current worktree to target in the history. The
+// history file is locked while it is updated, so switches in several shells at once do not
+// drop each other's entries.
+func (m *Manager) RecordSwitch(target string) error {
+	historyPath, err := historyFilePath()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := filelock.Lock(historyPath + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock history: %w", err)
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	entries := parseHistory(string(data))
+
+	now := time.Now()
+	if current, err := m.CurrentWorktree(); err == nil && current.Path != target {
+		// Record where we came from too, so 'switch -' can return there
+		entries = append(entries, historyEntry{Path: current.Path, Visited: now})
+	}
+	entries = append(entries, historyEntry{Path: target, Visited: now})
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	if err := filelock.WriteFile(historyPath, []byte(formatHistory(entries)), 0o644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// RecentWorktrees returns the worktrees from the switch history, most recently visited
+// first. Worktrees that no longer exist are left out.
+func (m *Manager) RecentWorktrees() ([]RecentWorktree, error) {
+	historyPath, err := historyFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(historyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	return recentWorktrees(parseHistory(string(data)), worktrees), nil
+}
+
+// PreviousWorktree returns the most recently visited worktree other than the current one
+func (m *Manager) PreviousWorktree() (string, error) {
+	recent, err := m.RecentWorktrees()
+	if err != nil {
+		return "", err
+	}
+
+	current := ""
+	if wt, err := m.CurrentWorktree(); err == nil {
+		current = wt.Path
+	}
+	for _, r := range recent {
+		if r.Worktree.Path != current {
+			return r.Worktree.Path, nil
+		}
+	}
+	return "", fmt.Errorf("no previous worktree in the switch history")
+}
+
+// recentWorktrees keeps the latest visit of each worktree in worktrees, newest first
+func recentWorktrees(entries []historyEntry, worktrees []Worktree) []RecentWorktree {
+	byPath := make(map[string]Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		byPath[wt.Path] = wt
+	}
+
+	seen := make(map[string]bool)
+	var recent []RecentWorktree
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		wt, ok := byPath[entry.Path]
+		if !ok || seen[entry.Path] {
+			continue
+		}
+		seen[entry.Path] = true
+		recent = append(recent, RecentWorktree{Worktree: wt, Visited: entry.Visited})
+	}
+	return recent
+}
+
+// parseHistory parses lines of "<unix time>\t<path>", oldest first, skipping malformed lines
+func parseHistory(data string) []historyEntry {
+	var entries []historyEntry
+	for _, line := range strings.Split(data, "\n") {
+		timestamp, path, ok := strings.Cut(line, "\t")
+		if !ok || path == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{Path: path, Visited: time.Unix(seconds, 0)})
+	}
+	return entries
+}
+
+func formatHistory(entries []historyEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "%d\t%s\n", entry.Visited.Unix(), entry.Path)
+	}
+	return b.String()
+}
+
+// historyFilePath returns the path of the history file of the current repository
+func historyFilePath() (string, error) {
+	commonDir, err := gitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, historyFileName), nil
+}
@@ -0,0 +1,51 @@
+package worktree
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHistory(t *testing.T) {
+	data := "1700000000\t/repo\n" +
+		"malformed\n" +
+		"notatime\t/repo/a\n" +
+		"1700000100\t/repo/.git/.wkit-worktrees/feature\n"
+
+	entries := parseHistory(data)
+	expected := []historyEntry{
+		{Path: "/repo", Visited: time.Unix(1700000000, 0)},
+		{Path: "/repo/.git/.wkit-worktrees/feature", Visited: time.Unix(1700000100, 0)},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("parseHistory() = %v, want %v", entries, expected)
+	}
+
+	if got := parseHistory(formatHistory(expected)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseHistory(formatHistory()) = %v, want %v", got, expected)
+	}
+}
+
+func TestRecentWorktrees(t *testing.T) {
+	main := Worktree{Path: "/repo", Branch: "main"}
+	feature := Worktree{Path: "/wt/feature", Branch: "feature"}
+	fix := Worktree{Path: "/wt/fix", Branch: "fix"}
+
+	entries := []historyEntry{
+		{Path: "/repo", Visited: time.Unix(1, 0)},
+		{Path: "/wt/feature", Visited: time.Unix(2, 0)},
+		{Path: "/wt/removed", Visited: time.Unix(3, 0)},
+		{Path: "/repo", Visited: time.Unix(4, 0)},
+		{Path: "/wt/fix", Visited: time.Unix(5, 0)},
+	}
+
+	recent := recentWorktrees(entries, []Worktree{main, feature, fix})
+	expected := []RecentWorktree{
+		{Worktree: fix, Visited: time.Unix(5, 0)},
+		{Worktree: main, Visited: time.Unix(4, 0)},
+		{Worktree: feature, Visited: time.Unix(2, 0)},
+	}
+	if !reflect.DeepEqual(recent, expected) {
+		t.Errorf("recentWorktrees() = %v, want %v", recent, expected)
+	}
+}
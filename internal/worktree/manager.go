@@ -1,23 +1,34 @@
 package worktree
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// DefaultRemote is the remote that wkit fetches from and compares against
+const DefaultRemote = "origin"
+
 // Worktree represents a Git worktree
 type Worktree struct {
 	Path   string
 	Branch string
 	HEAD   string
+	Locked bool
 }
 
 // Manager handles Git worktree operations
 type Manager struct {
 	// repo *git.Repository // go-git の Repository オブジェクトは直接使わない
+
+	// IncludeIgnored makes status collection count ignored files, which walks ignored
+	// directories such as node_modules and is much slower in large worktrees
+	IncludeIgnored bool
 }
 
 // NewManager creates a new WorktreeManager
@@ -68,6 +79,10 @@ func parseWorktreeList(output string) ([]Worktree, error) {
 				branch := strings.TrimPrefix(line, "branch ")
 				currentWorktree.Branch = strings.TrimPrefix(branch, "refs/heads/")
 			}
+		} else if line == "locked" || strings.HasPrefix(line, "locked ") {
+			if currentWorktree != nil {
+				currentWorktree.Locked = true
+			}
 		}
 	}
 
@@ -86,7 +101,12 @@ func GetRepositoryRoot() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to execute git rev-parse --git-common-dir: %w", err)
 	}
-	gitDir := strings.TrimSpace(string(output))
+	// The path is relative to the current directory when run from a subdirectory
+	gitDir, err := filepath.Abs(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git common dir: %w", err)
+	}
+	gitDir = resolveSymlinks(gitDir)
 
 	// The repository root is the parent of .git directory
 	if strings.HasSuffix(gitDir, "/.git") {
@@ -114,7 +134,7 @@ func (m *Manager) AddWorktree(branch string, path string, baseBranch string) err
 		// Use -b flag to create new branch from specified base branch
 		// First check if base branch exists locally, otherwise try remote
 		var actualBaseBranch string
-		if strings.HasPrefix(baseBranch, "origin/") {
+		if strings.HasPrefix(baseBranch, DefaultRemote+"/") {
 			// Already has origin/ prefix, use as-is
 			actualBaseBranch = baseBranch
 		} else if m.branchExists(baseBranch) {
@@ -122,7 +142,7 @@ func (m *Manager) AddWorktree(branch string, path string, baseBranch string) err
 			actualBaseBranch = baseBranch
 		} else {
 			// Try remote branch
-			actualBaseBranch = fmt.Sprintf("origin/%s", baseBranch)
+			actualBaseBranch = RemoteBranch(baseBranch)
 		}
 		cmdArgs = append(cmdArgs, "-b", branch, path, actualBaseBranch)
 	} else {
@@ -138,6 +158,32 @@ func (m *Manager) AddWorktree(branch string, path string, baseBranch string) err
 	return nil
 }
 
+// AddDetachedWorktree adds a worktree with a detached HEAD at the given commit-ish
+func (m *Manager) AddDetachedWorktree(path string, commitish string) error {
+	cmd := exec.Command("git", "worktree", "add", "--detach", path, commitish)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git worktree add: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// FindWorktreeByBranch returns the worktree that has branch checked out, or nil if there is none
+func (m *Manager) FindWorktreeByBranch(branch string) (*Worktree, error) {
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			return &wt, nil
+		}
+	}
+	return nil, nil
+}
+
 // branchExists checks if a local branch exists
 func (m *Manager) branchExists(branch string) bool {
 	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
@@ -145,94 +191,246 @@ func (m *Manager) branchExists(branch string) bool {
 	return err == nil
 }
 
-// FindWorktreePath finds a worktree path by name or partial path
+// ListBranches lists local branches and remote-tracking branches such as "origin/main"
+func (m *Manager) ListBranches() (local []string, remote []string, err error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute git for-each-ref: %w", err)
+	}
+
+	for _, ref := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			local = append(local, name)
+		} else if name, ok := strings.CutPrefix(ref, "refs/remotes/"); ok && !strings.HasSuffix(name, "/HEAD") {
+			remote = append(remote, name)
+		}
+	}
+	return local, remote, nil
+}
+
+// FindWorktreePath finds a worktree path by name, ranking exact branch, path and directory
+// name matches above prefix and fuzzy matches. Several equally good candidates are an
+// *AmbiguousWorktreeError and no match at all is a *WorktreeNotFoundError.
 func (m *Manager) FindWorktreePath(name string) (string, error) {
 	worktrees, err := m.ListWorktrees()
 	if err != nil {
 		return "", err
 	}
 
-	// Exact match by branch name
-	for _, wt := range worktrees {
-		if wt.Branch == name {
-			return wt.Path, nil
-		}
+	wt, err := findWorktree(name, worktrees, false)
+	if err != nil {
+		return "", err
 	}
+	return wt.Path, nil
+}
 
-	// Partial match by path
-	for _, wt := range worktrees {
-		if strings.Contains(wt.Path, name) {
-			return wt.Path, nil
-		}
+// FindWorktreePathStrict is like FindWorktreePath but ignores fuzzy matches, so that
+// destructive commands only act on a worktree the name clearly refers to
+func (m *Manager) FindWorktreePathStrict(name string) (string, error) {
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return "", err
+	}
+
+	wt, err := findWorktree(name, worktrees, true)
+	if err != nil {
+		return "", err
+	}
+	return wt.Path, nil
+}
+
+// FindWorktreePathExact finds a worktree whose path, branch or directory name is exactly name.
+// It returns an empty path and no error when there is none, so callers can create it.
+func (m *Manager) FindWorktreePathExact(name string) (string, error) {
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("worktree '%s' not found", name)
+	wt, err := findExactWorktree(name, worktrees)
+	if err != nil || wt == nil {
+		return "", err
+	}
+	return wt.Path, nil
 }
 
 // WorktreeStatus represents the status of a worktree
 type WorktreeStatus struct {
-	IsClean   bool
-	Modified  int
-	Added     int
-	Deleted   int
-	Untracked int
-	Ahead     int
-	Behind    int
+	IsClean    bool   `json:"is_clean" yaml:"is_clean"`
+	Upstream   string `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Staged     int    `json:"staged" yaml:"staged"`     // entries with changes in the index
+	Unstaged   int    `json:"unstaged" yaml:"unstaged"` // entries with changes in the working tree
+	Modified   int    `json:"modified" yaml:"modified"`
+	Added      int    `json:"added" yaml:"added"`
+	Deleted    int    `json:"deleted" yaml:"deleted"`
+	Renamed    int    `json:"renamed" yaml:"renamed"`
+	Copied     int    `json:"copied" yaml:"copied"`
+	Conflicted int    `json:"conflicted" yaml:"conflicted"`
+	Untracked  int    `json:"untracked" yaml:"untracked"`
+	Ignored    int    `json:"ignored" yaml:"ignored"` // only counted with Manager.IncludeIgnored
+	Ahead      int    `json:"ahead" yaml:"ahead"`
+	Behind     int    `json:"behind" yaml:"behind"`
+	MainRef    string `json:"main_ref,omitempty" yaml:"main_ref,omitempty"` // remote main branch compared against, empty when unavailable
+	MainAhead  int    `json:"main_ahead" yaml:"main_ahead"`
+	MainBehind int    `json:"main_behind" yaml:"main_behind"`
+	// Operation in progress (rebase, am, merge, cherry-pick, revert or bisect), with rebase progress
+	Operation      string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	OperationStep  int    `json:"operation_step,omitempty" yaml:"operation_step,omitempty"`
+	OperationTotal int    `json:"operation_total,omitempty" yaml:"operation_total,omitempty"`
 }
 
+// gitWaitDelay bounds how long a git command killed by its context may keep its output pipes
+// open, e.g. through a hook or fsmonitor child that outlives it, before Wait gives up
+const gitWaitDelay = time.Second
+
 // GetWorktreeStatus gets the status of a specific worktree
 func (m *Manager) GetWorktreeStatus(worktreePath string) (*WorktreeStatus, error) {
-	cmd := exec.Command("git", "status", "--porcelain", "--ahead-behind")
+	return m.GetWorktreeStatusContext(context.Background(), worktreePath)
+}
+
+// GetWorktreeStatusContext gets the status of a specific worktree, killing git when ctx is done
+func (m *Manager) GetWorktreeStatusContext(ctx context.Context, worktreePath string) (*WorktreeStatus, error) {
+	// Optional locks are skipped so that a killed status never leaves index.lock behind
+	args := []string{"--no-optional-locks", "status", "--porcelain=v2", "--branch"}
+	if m.IncludeIgnored {
+		args = append(args, "--ignored")
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = worktreePath
+	cmd.WaitDelay = gitWaitDelay
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute git status for %s: %w", worktreePath, err)
 	}
 
-	return parseGitStatus(string(output))
+	status, err := parseGitStatus(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	status.Operation, status.OperationStep, status.OperationTotal, err = DetectOperation(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// CommitInfo describes the last commit of a worktree
+type CommitInfo struct {
+	Subject   string
+	Author    string
+	Committed time.Time
+}
+
+// GetCommitInfo gets the subject, author and commit time of HEAD in a worktree
+func (m *Manager) GetCommitInfo(worktreePath string) (*CommitInfo, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%s%x00%an%x00%ct", "HEAD")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git log for %s: %w", worktreePath, err)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(output), "\n"), "\x00")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected git log output %q", output)
+	}
+	committed, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit time %q: %w", fields[2], err)
+	}
+	return &CommitInfo{Subject: fields[0], Author: fields[1], Committed: time.Unix(committed, 0)}, nil
+}
+
+// RemoteBranch returns the remote-tracking name of branch, e.g. "origin/main"
+func RemoteBranch(branch string) string {
+	return fmt.Sprintf("%s/%s", DefaultRemote, branch)
+}
+
+// GetMainDivergenceContext counts the commits HEAD of a worktree is ahead of and behind the
+// remote-tracking mainBranch. Only local refs are used, so nothing is fetched. The returned
+// ref is empty when the remote-tracking branch does not exist.
+func (m *Manager) GetMainDivergenceContext(ctx context.Context, worktreePath string, mainBranch string) (ref string, ahead int, behind int, err error) {
+	ref = RemoteBranch(mainBranch)
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", "refs/remotes/"+ref)
+	cmd.Dir = worktreePath
+	cmd.WaitDelay = gitWaitDelay
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", 0, 0, ctx.Err()
+		}
+		return "", 0, 0, nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", "HEAD..."+ref)
+	cmd.Dir = worktreePath
+	cmd.WaitDelay = gitWaitDelay
+	output, err := cmd.Output()
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to execute git rev-list for %s: %w", worktreePath, err)
+	}
+	if _, err := fmt.Sscanf(string(output), "%d %d", &ahead, &behind); err != nil {
+		return "", 0, 0, fmt.Errorf("failed to parse rev-list output %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return ref, ahead, behind, nil
 }
 
+// parseGitStatus parses the output of 'git status --porcelain=v2 --branch'
 func parseGitStatus(output string) (*WorktreeStatus, error) {
 	status := &WorktreeStatus{}
 	lines := strings.Split(output, "\n")
 
 	for _, line := range lines {
-		if len(line) < 2 {
+		if line == "" {
 			continue
 		}
 
-		staged := string(line[0])
-		unstaged := string(line[1])
-
-		switch {
-		case staged == "A":
-			status.Added++
-		case staged == "M" || unstaged == "M":
-			status.Modified++
-		case staged == "D" || unstaged == "D":
-			status.Deleted++
-		case staged == "?" && unstaged == "?":
-			status.Untracked++
-		}
-
-		// Parse ahead/behind information
-		if strings.HasPrefix(line, "##") {
-			parts := strings.Fields(line)
-			for _, part := range parts {
-				if strings.HasPrefix(part, "ahead") {
-					if n, err := fmt.Sscanf(part, "ahead %d", &status.Ahead); err == nil && n == 1 {
-						// Successfully parsed
-					}
-				} else if strings.HasPrefix(part, "behind") {
-					if n, err := fmt.Sscanf(part, "behind %d", &status.Behind); err == nil && n == 1 {
-						// Successfully parsed
-					}
+		switch line[0] {
+		case '#':
+			if upstream, ok := strings.CutPrefix(line, "# branch.upstream "); ok {
+				status.Upstream = upstream
+			} else if ab, ok := strings.CutPrefix(line, "# branch.ab "); ok {
+				if _, err := fmt.Sscanf(ab, "+%d -%d", &status.Ahead, &status.Behind); err != nil {
+					return nil, fmt.Errorf("failed to parse ahead/behind %q: %w", ab, err)
 				}
 			}
+		case '1', '2':
+			// Ordinary ("1") and renamed or copied ("2") entries: "<type> <XY> ..."
+			if len(line) < 4 {
+				return nil, fmt.Errorf("malformed status line %q", line)
+			}
+			staged, unstaged := line[2], line[3]
+			if staged != '.' {
+				status.Staged++
+			}
+			if unstaged != '.' {
+				status.Unstaged++
+			}
+
+			switch {
+			case staged == 'R' || unstaged == 'R':
+				status.Renamed++
+			case staged == 'C' || unstaged == 'C':
+				status.Copied++
+			case staged == 'A' || unstaged == 'A':
+				status.Added++
+			case staged == 'D' || unstaged == 'D':
+				status.Deleted++
+			case staged == 'M' || unstaged == 'M' || staged == 'T' || unstaged == 'T':
+				status.Modified++
+			}
+		case 'u':
+			status.Conflicted++
+		case '?':
+			status.Untracked++
+		case '!':
+			status.Ignored++
 		}
 	}
 
-	status.IsClean = (status.Modified == 0 && status.Added == 0 && status.Deleted == 0 && status.Untracked == 0)
+	status.IsClean = status.Staged == 0 && status.Unstaged == 0 && status.Conflicted == 0 && status.Untracked == 0
 
 	return status, nil
 }
@@ -308,7 +506,7 @@ func (m *Manager) getAllMergedBranches(mainBranch string) ([]string, error) {
 }
 
 func (m *Manager) getAllRemoteBranches() ([]string, error) {
-	cmd := exec.Command("git", "ls-remote", "--heads", "origin")
+	cmd := exec.Command("git", "ls-remote", "--heads", DefaultRemote)
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute git ls-remote: %w", err)
@@ -342,7 +540,7 @@ func containsString(slice []string, s string) bool {
 // SyncWorktreeWithBranch syncs a worktree with the main branch
 func (m *Manager) SyncWorktreeWithBranch(worktreePath string, mainBranch string, useRebase bool) error {
 	// First, fetch latest changes
-	cmd := exec.Command("git", "fetch", "origin")
+	cmd := exec.Command("git", "fetch", DefaultRemote)
 	cmd.Dir = worktreePath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -350,7 +548,7 @@ func (m *Manager) SyncWorktreeWithBranch(worktreePath string, mainBranch string,
 	}
 
 	// Then sync with specified branch
-	originBranch := fmt.Sprintf("origin/%s", mainBranch)
+	originBranch := RemoteBranch(mainBranch)
 	var syncCmdArgs []string
 	if useRebase {
 		syncCmdArgs = []string{"rebase", originBranch}
@@ -378,30 +576,90 @@ func (m *Manager) RemoveWorktree(worktreePath string) error {
 	return nil
 }
 
-// GetRelativePathFromRoot returns the relative path from the git repository root to the current working directory
-func GetRelativePathFromRoot() (string, error) {
-	// Get current working directory
+// LockWorktree locks a worktree so that it is not pruned, moved or removed
+func (m *Manager) LockWorktree(worktreePath string, reason string) error {
+	cmdArgs := []string{"worktree", "lock"}
+	if reason != "" {
+		cmdArgs = append(cmdArgs, "--reason", reason)
+	}
+	cmdArgs = append(cmdArgs, worktreePath)
+
+	cmd := exec.Command("git", cmdArgs...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git worktree lock: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// UnlockWorktree unlocks a locked worktree
+func (m *Manager) UnlockWorktree(worktreePath string) error {
+	cmd := exec.Command("git", "worktree", "unlock", worktreePath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git worktree unlock: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MoveWorktree moves a worktree to a new location, creating parent directories as needed
+func (m *Manager) MoveWorktree(worktreePath string, newPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	cmd := exec.Command("git", "worktree", "move", worktreePath, newPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git worktree move: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// TargetDirectory returns the directory to change to when switching to worktreePath: the
+// directory at the same path relative to the worktree top level as the current directory,
+// or its deepest ancestor that exists in the target worktree
+func TargetDirectory(worktreePath string) string {
+	relativePath, err := relativePathInWorktree()
+	if err != nil {
+		return worktreePath
+	}
+	return deepestExistingDir(worktreePath, relativePath)
+}
+
+// relativePathInWorktree returns the path of the current directory relative to the top level
+// of the worktree containing it, which is empty at the top level
+func relativePathInWorktree() (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current working directory: %w", err)
 	}
+	currentDir = resolveSymlinks(currentDir)
 
-	// Get repository root
-	repoRoot, err := GetRepositoryRoot()
+	toplevel, err := CurrentWorktreePath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get repository root: %w", err)
+		return "", err
 	}
 
-	// Get relative path from repo root to current directory
-	relativePath, err := filepath.Rel(repoRoot, currentDir)
+	relativePath, err := filepath.Rel(toplevel, currentDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to get relative path: %w", err)
 	}
-
-	// If the relative path is ".", it means we're at the repository root
-	if relativePath == "." {
+	if relativePath == "." || relativePath == ".." || strings.HasPrefix(relativePath, "../") {
 		return "", nil
 	}
-
 	return relativePath, nil
 }
+
+// deepestExistingDir returns root/relativePath, or its deepest ancestor below root that is
+// an existing directory
+func deepestExistingDir(root string, relativePath string) string {
+	dir := filepath.Join(root, relativePath)
+	for dir != root && len(dir) > len(root) {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		dir = filepath.Dir(dir)
+	}
+	return root
+}
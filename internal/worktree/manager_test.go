@@ -2,8 +2,11 @@ package worktree
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseWorktreeList(t *testing.T) {
@@ -18,6 +21,7 @@ branch refs/heads/feature-branch
 worktree /path/to/repo/.git/.wkit-worktrees/another-branch
 HEAD fedcba0987654321
 branch refs/heads/another-branch
+locked on a removable drive
 `
 
 	worktrees, err := parseWorktreeList(testOutput)
@@ -51,6 +55,14 @@ branch refs/heads/another-branch
 	if worktrees[1].HEAD != "abcdef1234567890" {
 		t.Errorf("Expected HEAD 'abcdef1234567890', got %s", worktrees[1].HEAD)
 	}
+	if worktrees[1].Locked {
+		t.Error("Expected feature-branch worktree to be unlocked")
+	}
+
+	// Test third worktree (locked with a reason)
+	if !worktrees[2].Locked {
+		t.Error("Expected another-branch worktree to be locked")
+	}
 }
 
 func TestParseGitStatus(t *testing.T) {
@@ -60,33 +72,66 @@ func TestParseGitStatus(t *testing.T) {
 		expected *WorktreeStatus
 	}{
 		{
-			name:   "clean status",
-			output: "",
+			name: "clean status",
+			output: `# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head main
+`,
 			expected: &WorktreeStatus{
-				IsClean:   true,
-				Modified:  0,
-				Added:     0,
-				Deleted:   0,
-				Untracked: 0,
-				Ahead:     0,
-				Behind:    0,
+				IsClean: true,
 			},
 		},
 		{
 			name: "modified files",
-			output: ` M file1.go
- M file2.go
-A  file3.go
- D file4.go
-?? file5.go`,
+			output: `# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -3
+1 .M N... 100644 100644 100644 1111111 1111111 file1.go
+1 M. N... 100644 100644 100644 1111111 2222222 file2.go
+1 A. N... 000000 100644 100644 0000000 3333333 file3.go
+1 .D N... 100644 100644 000000 4444444 4444444 file4.go
+? file5.go`,
 			expected: &WorktreeStatus{
 				IsClean:   false,
+				Upstream:  "origin/feature",
+				Staged:    2,
+				Unstaged:  2,
 				Modified:  2,
 				Added:     1,
 				Deleted:   1,
 				Untracked: 1,
-				Ahead:     0,
-				Behind:    0,
+				Ahead:     2,
+				Behind:    3,
+			},
+		},
+		{
+			name: "renames, copies, conflicts and ignored files",
+			output: `# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head feature
+2 R. N... 100644 100644 100644 1111111 1111111 R100 new.go	old.go
+2 CM N... 100644 100644 100644 1111111 1111111 C75 copy.go	orig.go
+1 MM N... 100644 100644 100644 1111111 2222222 both.go
+u UU N... 100644 100644 100644 100644 1111111 2222222 3333333 conflict.go
+! build/`,
+			expected: &WorktreeStatus{
+				IsClean:    false,
+				Staged:     3,
+				Unstaged:   2,
+				Modified:   1,
+				Renamed:    1,
+				Copied:     1,
+				Conflicted: 1,
+				Ignored:    1,
+			},
+		},
+		{
+			name: "only ignored files",
+			output: `# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head main
+! node_modules/`,
+			expected: &WorktreeStatus{
+				IsClean: true,
+				Ignored: 1,
 			},
 		},
 	}
@@ -98,20 +143,8 @@ A  file3.go
 				t.Fatalf("parseGitStatus() failed: %v", err)
 			}
 
-			if result.IsClean != tt.expected.IsClean {
-				t.Errorf("IsClean = %v, want %v", result.IsClean, tt.expected.IsClean)
-			}
-			if result.Modified != tt.expected.Modified {
-				t.Errorf("Modified = %v, want %v", result.Modified, tt.expected.Modified)
-			}
-			if result.Added != tt.expected.Added {
-				t.Errorf("Added = %v, want %v", result.Added, tt.expected.Added)
-			}
-			if result.Deleted != tt.expected.Deleted {
-				t.Errorf("Deleted = %v, want %v", result.Deleted, tt.expected.Deleted)
-			}
-			if result.Untracked != tt.expected.Untracked {
-				t.Errorf("Untracked = %v, want %v", result.Untracked, tt.expected.Untracked)
+			if *result != *tt.expected {
+				t.Errorf("parseGitStatus() = %+v, want %+v", *result, *tt.expected)
 			}
 		})
 	}
@@ -225,3 +258,59 @@ func TestAddWorktree_BaseBranchHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStashList(t *testing.T) {
+	output := "stash@{0}\tabc123\t1700000000\tWIP on feature/x: 1234567 fix parser\n" +
+		"stash@{1}\tdef456\t1690000000\tOn main: before release\n" +
+		"stash@{2}\t789abc\t1680000000\tWIP on (no branch): 89abcde detached work\n"
+
+	stashes, err := parseStashList(output)
+	if err != nil {
+		t.Fatalf("parseStashList() failed: %v", err)
+	}
+
+	expected := []Stash{
+		{Index: 0, Ref: "stash@{0}", Hash: "abc123", Branch: "feature/x", Message: "1234567 fix parser", Created: time.Unix(1700000000, 0)},
+		{Index: 1, Ref: "stash@{1}", Hash: "def456", Branch: "main", Message: "before release", Created: time.Unix(1690000000, 0)},
+		{Index: 2, Ref: "stash@{2}", Hash: "789abc", Branch: "", Message: "89abcde detached work", Created: time.Unix(1680000000, 0)},
+	}
+	if len(stashes) != len(expected) {
+		t.Fatalf("Expected %d stashes, got %d", len(expected), len(stashes))
+	}
+	for i := range expected {
+		if stashes[i] != expected[i] {
+			t.Errorf("stashes[%d] = %+v, want %+v", i, stashes[i], expected[i])
+		}
+	}
+
+	if _, err := parseStashList("garbage\n"); err == nil {
+		t.Error("Expected an error for a malformed line")
+	}
+}
+
+func TestDeepestExistingDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src", "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		relativePath string
+		expected     string
+	}{
+		{"", root},
+		{"src/api", filepath.Join(root, "src", "api")},
+		{"src/web/components", filepath.Join(root, "src")},
+		{"docs/guide", root},
+		{"README.md", root}, // files are not directories to change to
+	}
+
+	for _, tt := range tests {
+		if got := deepestExistingDir(root, tt.relativePath); got != tt.expected {
+			t.Errorf("deepestExistingDir(%q) = %s, want %s", tt.relativePath, got, tt.expected)
+		}
+	}
+}
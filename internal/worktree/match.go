@@ -0,0 +1,178 @@
+package worktree
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"wkit/internal/fuzzy"
+)
+
+// How well a name matches a worktree, best first
+const (
+	matchFuzzy = iota + 1
+	matchPrefix
+	matchBasename
+	matchBranch
+	matchPath
+)
+
+// fuzzyAmbiguityMargin is how close to the best fuzzy score another candidate must be
+// for the name to count as ambiguous
+const fuzzyAmbiguityMargin = 8
+
+// worktreeMatch is a worktree that matches a name
+type worktreeMatch struct {
+	worktree Worktree
+	kind     int
+	score    int // fuzzy score, only set for fuzzy matches
+}
+
+// AmbiguousWorktreeError is returned when a name matches several worktrees equally well
+type AmbiguousWorktreeError struct {
+	Name       string
+	Candidates []Worktree
+}
+
+func (e *AmbiguousWorktreeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "'%s' matches several worktrees; use a more specific name:", e.Name)
+	for _, wt := range e.Candidates {
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		fmt.Fprintf(&b, "\n  %s (%s)", branch, wt.Path)
+	}
+	return b.String()
+}
+
+// WorktreeNotFoundError is returned when a name matches no worktree at all
+type WorktreeNotFoundError struct {
+	Name string
+}
+
+func (e *WorktreeNotFoundError) Error() string {
+	return fmt.Sprintf("worktree '%s' not found", e.Name)
+}
+
+// findWorktree returns the worktree that name matches best. With strict, only exact path,
+// branch and directory name matches are accepted; prefix and fuzzy matches are only listed
+// as suggestions, so destructive commands never act on a worktree the name merely resembles.
+func findWorktree(name string, worktrees []Worktree, strict bool) (*Worktree, error) {
+	matches := rankWorktrees(name, worktrees)
+	if len(matches) == 0 {
+		return nil, &WorktreeNotFoundError{Name: name}
+	}
+
+	best := matches[0]
+	var candidates []Worktree
+	for _, match := range matches {
+		if match.kind != best.kind {
+			break
+		}
+		if match.kind == matchFuzzy && match.score < best.score-fuzzyAmbiguityMargin {
+			break
+		}
+		candidates = append(candidates, match.worktree)
+	}
+
+	if strict && best.kind < matchBasename {
+		var suggestions []string
+		for _, wt := range candidates {
+			if wt.Branch != "" {
+				suggestions = append(suggestions, wt.Branch)
+			} else {
+				suggestions = append(suggestions, wt.Path)
+			}
+		}
+		return nil, fmt.Errorf("worktree '%s' not found; did you mean: %s", name, strings.Join(suggestions, ", "))
+	}
+	if len(candidates) > 1 {
+		return nil, &AmbiguousWorktreeError{Name: name, Candidates: candidates}
+	}
+	return &best.worktree, nil
+}
+
+// findExactWorktree returns the worktree whose path, branch or directory name is name, or
+// nil when there is none
+func findExactWorktree(name string, worktrees []Worktree) (*Worktree, error) {
+	matches := rankWorktrees(name, worktrees)
+	if len(matches) == 0 || matches[0].kind < matchBasename {
+		return nil, nil
+	}
+
+	best := matches[0]
+	var candidates []Worktree
+	for _, match := range matches {
+		if match.kind != best.kind {
+			break
+		}
+		candidates = append(candidates, match.worktree)
+	}
+	if len(candidates) > 1 {
+		return nil, &AmbiguousWorktreeError{Name: name, Candidates: candidates}
+	}
+	return &best.worktree, nil
+}
+
+// rankWorktrees returns the worktrees that match name, best first. Worktrees are matched by
+// exact path, exact branch, exact directory name, branch or directory name prefix, and
+// finally fuzzily against the branch and the path relative to the main worktree.
+func rankWorktrees(name string, worktrees []Worktree) []worktreeMatch {
+	if name == "" || len(worktrees) == 0 {
+		return nil
+	}
+
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		absName = name
+	}
+	// The main worktree is listed first; other paths are matched relative to it
+	mainRoot := worktrees[0].Path
+
+	var matches []worktreeMatch
+	for _, wt := range worktrees {
+		basename := filepath.Base(wt.Path)
+		match := worktreeMatch{worktree: wt}
+		switch {
+		case wt.Path == name || wt.Path == absName:
+			match.kind = matchPath
+		case wt.Branch == name:
+			match.kind = matchBranch
+		case basename == name:
+			match.kind = matchBasename
+		case (wt.Branch != "" && strings.HasPrefix(wt.Branch, name)) || strings.HasPrefix(basename, name):
+			match.kind = matchPrefix
+		default:
+			relativePath, err := filepath.Rel(mainRoot, wt.Path)
+			if err != nil {
+				relativePath = wt.Path
+			}
+			branchScore, _, branchOK := fuzzy.Match(name, wt.Branch)
+			pathScore, _, pathOK := fuzzy.Match(name, relativePath)
+			if !branchOK && !pathOK {
+				continue
+			}
+			match.kind = matchFuzzy
+			switch {
+			case branchOK && pathOK:
+				match.score = max(branchScore, pathScore)
+			case branchOK:
+				match.score = branchScore
+			default:
+				match.score = pathScore
+			}
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].kind != matches[j].kind {
+			return matches[i].kind > matches[j].kind
+		}
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
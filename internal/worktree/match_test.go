@@ -0,0 +1,109 @@
+package worktree
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFindWorktree(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/.git/.wkit-worktrees/api-v2", Branch: "api-v2"},
+		{Path: "/repo/.git/.wkit-worktrees/api", Branch: "api"},
+		{Path: "/repo/.git/.wkit-worktrees/feature/login-form", Branch: "feature/login-form"},
+		{Path: "/repo/.git/.wkit-worktrees/feature/logout", Branch: "feature/logout"},
+		{Path: "/elsewhere/hotfix-dir", Branch: "hotfix/crash"},
+		{Path: "/repo/.git/.wkit-worktrees/detached"},
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		strict    bool
+		expected  string
+		ambiguous bool
+		hasError  bool
+		suggests  []string // candidates the error must list
+	}{
+		{name: "exact branch beats containing path", query: "api", expected: "/repo/.git/.wkit-worktrees/api"},
+		{name: "exact path", query: "/elsewhere/hotfix-dir", expected: "/elsewhere/hotfix-dir"},
+		{name: "exact directory name", query: "hotfix-dir", expected: "/elsewhere/hotfix-dir"},
+		{name: "unique branch prefix", query: "hotfix", expected: "/elsewhere/hotfix-dir"},
+		{name: "ambiguous prefix", query: "feature/log", ambiguous: true},
+		{name: "fuzzy match", query: "lgnfrm", expected: "/repo/.git/.wkit-worktrees/feature/login-form"},
+		{name: "detached worktree by directory name", query: "detached", expected: "/repo/.git/.wkit-worktrees/detached"},
+		{name: "not found", query: "zzz", hasError: true},
+		{name: "strict accepts exact branch", query: "api", strict: true, expected: "/repo/.git/.wkit-worktrees/api"},
+		{name: "strict accepts exact directory name", query: "hotfix-dir", strict: true, expected: "/elsewhere/hotfix-dir"},
+		{name: "strict rejects unique prefix", query: "hotfix", strict: true, hasError: true, suggests: []string{"hotfix/crash"}},
+		{name: "strict rejects prefix of a longer branch", query: "api-", strict: true, hasError: true, suggests: []string{"api-v2"}},
+		{name: "strict rejects fuzzy match", query: "lgnfrm", strict: true, hasError: true},
+		{name: "strict rejects ambiguous prefix", query: "feature/log", strict: true, hasError: true, suggests: []string{"feature/login-form", "feature/logout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt, err := findWorktree(tt.query, worktrees, tt.strict)
+
+			var ambiguousErr *AmbiguousWorktreeError
+			switch {
+			case tt.ambiguous:
+				if !errors.As(err, &ambiguousErr) {
+					t.Fatalf("Expected AmbiguousWorktreeError, got %v", err)
+				}
+				if len(ambiguousErr.Candidates) != 2 || !strings.Contains(err.Error(), "feature/logout") {
+					t.Errorf("Unexpected ambiguity error: %v", err)
+				}
+			case tt.hasError:
+				if err == nil {
+					t.Fatalf("Expected error, got %s", wt.Path)
+				}
+				for _, candidate := range tt.suggests {
+					if !strings.Contains(err.Error(), candidate) {
+						t.Errorf("Error does not suggest %s: %v", candidate, err)
+					}
+				}
+			case err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case wt.Path != tt.expected:
+				t.Errorf("findWorktree(%q) = %s, want %s", tt.query, wt.Path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindExactWorktree(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/.git/.wkit-worktrees/feature/login", Branch: "feature/login"},
+		{Path: "/elsewhere/hotfix-dir", Branch: "hotfix/crash"},
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "feature/login", expected: "/repo/.git/.wkit-worktrees/feature/login"},
+		{query: "hotfix-dir", expected: "/elsewhere/hotfix-dir"},
+		{query: "/repo", expected: "/repo"},
+		{query: "feature/log"}, // prefixes are not exact
+		{query: "ftlgn"},       // neither are fuzzy matches
+		{query: "feature/new"},
+	}
+
+	for _, tt := range tests {
+		wt, err := findExactWorktree(tt.query, worktrees)
+		if err != nil {
+			t.Errorf("findExactWorktree(%q) failed: %v", tt.query, err)
+			continue
+		}
+		got := ""
+		if wt != nil {
+			got = wt.Path
+		}
+		if got != tt.expected {
+			t.Errorf("findExactWorktree(%q) = %q, want %q", tt.query, got, tt.expected)
+		}
+	}
+}
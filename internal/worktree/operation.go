@@ -0,0 +1,84 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Operations that can be in progress in a worktree
+const (
+	OperationRebase     = "rebase"
+	OperationAm         = "am"
+	OperationMerge      = "merge"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+	OperationBisect     = "bisect"
+)
+
+// DetectOperation reports the operation in progress in a worktree, if any, by looking at the
+// state files git keeps in the worktree's git dir. Step and total are only known for rebase and am.
+func DetectOperation(worktreePath string) (name string, step int, total int, err error) {
+	gitDir, err := worktreeGitDir(worktreePath)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if dir := filepath.Join(gitDir, "rebase-merge"); isDir(dir) {
+		return OperationRebase, readCounter(dir, "msgnum"), readCounter(dir, "end"), nil
+	}
+	if dir := filepath.Join(gitDir, "rebase-apply"); isDir(dir) {
+		name = OperationRebase
+		if fileExists(filepath.Join(dir, "applying")) {
+			name = OperationAm
+		}
+		return name, readCounter(dir, "next"), readCounter(dir, "last"), nil
+	}
+
+	for _, state := range []struct{ file, name string }{
+		{"MERGE_HEAD", OperationMerge},
+		{"CHERRY_PICK_HEAD", OperationCherryPick},
+		{"REVERT_HEAD", OperationRevert},
+		{"BISECT_LOG", OperationBisect},
+	} {
+		if fileExists(filepath.Join(gitDir, state.file)) {
+			return state.name, 0, 0, nil
+		}
+	}
+	return "", 0, 0, nil
+}
+
+// worktreeGitDir returns the git dir of a worktree: .git itself for the main worktree,
+// or the directory its .git file points to for a linked one
+func worktreeGitDir(worktreePath string) (string, error) {
+	dotGit := filepath.Join(worktreePath, ".git")
+	if isDir(dotGit) {
+		return dotGit, nil
+	}
+	if gitDir, ok := readGitDirFile(worktreePath); ok {
+		return gitDir, nil
+	}
+	return "", fmt.Errorf("failed to find git dir of %s", worktreePath)
+}
+
+// readCounter reads a number from a rebase state file, returning 0 when it is missing
+func readCounter(dir string, name string) int {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(content)))
+	return n
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
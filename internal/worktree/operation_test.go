@@ -0,0 +1,99 @@
+package worktree
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectOperation(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         map[string]string
+		expectedName  string
+		expectedStep  int
+		expectedTotal int
+	}{
+		{
+			name:         "nothing in progress",
+			files:        map[string]string{"HEAD": "ref: refs/heads/main\n"},
+			expectedName: "",
+		},
+		{
+			name:          "interactive rebase",
+			files:         map[string]string{"rebase-merge/msgnum": "3\n", "rebase-merge/end": "7\n"},
+			expectedName:  OperationRebase,
+			expectedStep:  3,
+			expectedTotal: 7,
+		},
+		{
+			name:          "apply rebase",
+			files:         map[string]string{"rebase-apply/next": "1\n", "rebase-apply/last": "2\n", "rebase-apply/rebasing": ""},
+			expectedName:  OperationRebase,
+			expectedStep:  1,
+			expectedTotal: 2,
+		},
+		{
+			name:          "am",
+			files:         map[string]string{"rebase-apply/next": "2\n", "rebase-apply/last": "4\n", "rebase-apply/applying": ""},
+			expectedName:  OperationAm,
+			expectedStep:  2,
+			expectedTotal: 4,
+		},
+		{
+			name:         "merge",
+			files:        map[string]string{"MERGE_HEAD": "abc\n"},
+			expectedName: OperationMerge,
+		},
+		{
+			name:         "cherry-pick",
+			files:        map[string]string{"CHERRY_PICK_HEAD": "abc\n"},
+			expectedName: OperationCherryPick,
+		},
+		{
+			name:         "revert",
+			files:        map[string]string{"REVERT_HEAD": "abc\n"},
+			expectedName: OperationRevert,
+		},
+		{
+			name:         "bisect",
+			files:        map[string]string{"BISECT_LOG": "git bisect start\n"},
+			expectedName: OperationBisect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worktreePath := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(worktreePath, ".git", name)
+				mustMkdir(t, filepath.Dir(path))
+				mustWriteFile(t, path, content)
+			}
+
+			name, step, total, err := DetectOperation(worktreePath)
+			if err != nil {
+				t.Fatalf("DetectOperation() failed: %v", err)
+			}
+			if name != tt.expectedName || step != tt.expectedStep || total != tt.expectedTotal {
+				t.Errorf("DetectOperation() = %q, %d, %d, want %q, %d, %d", name, step, total, tt.expectedName, tt.expectedStep, tt.expectedTotal)
+			}
+		})
+	}
+}
+
+func TestDetectOperationLinkedWorktree(t *testing.T) {
+	gitDir := filepath.Join(t.TempDir(), "worktrees", "feature")
+	mustMkdir(t, gitDir)
+	mustWriteFile(t, filepath.Join(gitDir, "MERGE_HEAD"), "abc\n")
+
+	worktreePath := t.TempDir()
+	mustWriteFile(t, filepath.Join(worktreePath, ".git"), "gitdir: "+gitDir+"\n")
+
+	name, _, _, err := DetectOperation(worktreePath)
+	if err != nil {
+		t.Fatalf("DetectOperation() failed: %v", err)
+	}
+	if name != OperationMerge {
+		t.Errorf("DetectOperation() = %q, want %q", name, OperationMerge)
+	}
+}
@@ -0,0 +1,380 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// OrphanedDirectory represents a directory under wkit_root that is not a registered worktree
+type OrphanedDirectory struct {
+	Path       string
+	Size       int64
+	IsCheckout bool // the directory still has a .git file pointing at this repository
+	IsForeign  bool // the directory has a .git file pointing at another repository
+}
+
+// FindOrphanedDirectories finds directories under wkitRoot that are no longer registered worktrees
+func (m *Manager) FindOrphanedDirectories(wkitRoot string) ([]OrphanedDirectory, error) {
+	return m.findOrphans(wkitRoot, false)
+}
+
+// FindOrphanedCheckouts finds unregistered checkouts of this repository under root. Unlike
+// FindOrphanedDirectories it leaves every other directory alone, so it is safe to use on
+// directories shared with other projects, such as the root of an absolute path_template.
+func (m *Manager) FindOrphanedCheckouts(root string) ([]OrphanedDirectory, error) {
+	return m.findOrphans(root, true)
+}
+
+func (m *Manager) findOrphans(root string, checkoutsOnly bool) ([]OrphanedDirectory, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	commonDir, err := gitCommonDir()
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		registered[filepath.Clean(wt.Path)] = true
+	}
+
+	var paths []string
+	if err := collectOrphanedPaths(filepath.Clean(root), registered, &paths); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	orphans := make([]OrphanedDirectory, 0, len(paths))
+	for _, path := range paths {
+		_, hasGitDir := readGitDirFile(path)
+		_, isCheckout := ownedGitDir(path, commonDir)
+		if checkoutsOnly && !isCheckout {
+			continue
+		}
+
+		size, err := DirSize(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate size of %s: %w", path, err)
+		}
+		orphans = append(orphans, OrphanedDirectory{
+			Path:       path,
+			Size:       size,
+			IsCheckout: isCheckout,
+			IsForeign:  hasGitDir && !isCheckout,
+		})
+	}
+
+	return orphans, nil
+}
+
+func collectOrphanedPaths(dir string, registered map[string]bool, paths *[]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if registered[path] {
+			continue
+		}
+
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			*paths = append(*paths, path)
+			continue
+		}
+
+		// Directories such as "feature" for "feature/foo" only group other worktrees
+		if containsRegistered(path, registered) || containsGitEntry(path) {
+			if err := collectOrphanedPaths(path, registered, paths); err != nil {
+				return err
+			}
+			continue
+		}
+
+		*paths = append(*paths, path)
+	}
+
+	return nil
+}
+
+func containsRegistered(dir string, registered map[string]bool) bool {
+	prefix := dir + string(filepath.Separator)
+	for path := range registered {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var errFound = errors.New("found")
+
+func containsGitEntry(dir string) bool {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return errFound
+		}
+		return nil
+	})
+	return errors.Is(err, errFound)
+}
+
+// readGitDirFile returns the git directory referenced by the .git file of a linked checkout
+func readGitDirFile(path string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", false
+	}
+
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", false
+	}
+
+	gitDir := strings.TrimPrefix(line, "gitdir: ")
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	return gitDir, true
+}
+
+// ownedGitDir returns the git directory of the linked checkout at path when it is one of
+// the worktree directories of the repository whose common dir is commonDir. The checkout
+// of another repository left at the same place is not ours to adopt.
+func ownedGitDir(path string, commonDir string) (string, bool) {
+	gitDir, ok := readGitDirFile(path)
+	if !ok {
+		return "", false
+	}
+
+	// The git dir itself may be gone, so only its parent is resolved
+	resolved := filepath.Join(resolveSymlinks(filepath.Dir(gitDir)), filepath.Base(gitDir))
+	worktreesDir := filepath.Join(resolveSymlinks(commonDir), "worktrees")
+	if filepath.Dir(resolved) != worktreesDir {
+		return "", false
+	}
+	return gitDir, true
+}
+
+// gitCommonDir returns the absolute git common dir of the current repository, shared by
+// every worktree
+func gitCommonDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git rev-parse --git-common-dir: %w", err)
+	}
+
+	commonDir, err := filepath.Abs(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git common dir: %w", err)
+	}
+	return resolveSymlinks(commonDir), nil
+}
+
+// DirSize returns the total size of all regular files under path
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// WorktreeSize returns the size of the files checked out in a worktree. The .git directory
+// and nested checkouts, such as worktrees kept inside the main worktree, are not counted.
+func WorktreeSize(worktreePath string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(worktreePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || (path != worktreePath && fileExists(filepath.Join(path, ".git"))) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// CheckoutBranch returns the branch an orphaned checkout of this repository has checked out,
+// read from the HEAD in its administrative files, or "" when those are gone or HEAD is
+// detached
+func (m *Manager) CheckoutBranch(path string) string {
+	commonDir, err := gitCommonDir()
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := ownedGitDir(path, commonDir)
+	if !ok {
+		return ""
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+	if !ok {
+		return ""
+	}
+	return branch
+}
+
+// BranchFromWkitPath guesses the branch of a directory laid out as wkit_root/<branch>
+func BranchFromWkitPath(wkitRoot string, path string) string {
+	relativePath, err := filepath.Rel(wkitRoot, path)
+	if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") {
+		return ""
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+// AdoptWorktree re-registers an orphaned checkout at path as a worktree of branch
+func (m *Manager) AdoptWorktree(path string, branch string) error {
+	commonDir, err := gitCommonDir()
+	if err != nil {
+		return err
+	}
+	gitDir, ok := ownedGitDir(path, commonDir)
+	if !ok {
+		return fmt.Errorf("'%s' does not contain a worktree checkout of this repository", path)
+	}
+
+	// The administrative files still exist, so git only needs to fix the links
+	if _, err := os.Stat(gitDir); err == nil {
+		cmd := exec.Command("git", "worktree", "repair", path)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to execute git worktree repair: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	if branch == "" {
+		return fmt.Errorf("cannot determine the branch of '%s'", path)
+	}
+	if !m.branchExists(branch) {
+		return fmt.Errorf("branch '%s' does not exist", branch)
+	}
+
+	// Register a fresh worktree without checkout, then move the existing files into it
+	stagingPath := path + ".wkit-adopt"
+	if err := os.Rename(path, stagingPath); err != nil {
+		return fmt.Errorf("failed to move '%s' aside: %w", path, err)
+	}
+
+	cmd := exec.Command("git", "worktree", "add", "--no-checkout", path, branch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if restoreErr := os.Rename(stagingPath, path); restoreErr != nil {
+			return fmt.Errorf("failed to execute git worktree add: %w: %s (files left at %s)", err, strings.TrimSpace(string(output)), stagingPath)
+		}
+		return fmt.Errorf("failed to execute git worktree add: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	entries, err := os.ReadDir(stagingPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", stagingPath, err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		if err := os.Rename(filepath.Join(stagingPath, entry.Name()), filepath.Join(path, entry.Name())); err != nil {
+			return fmt.Errorf("failed to move %s into adopted worktree: %w", entry.Name(), err)
+		}
+	}
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", stagingPath, err)
+	}
+
+	// Populate the index from HEAD without touching the adopted files
+	cmd = exec.Command("git", "reset", "--quiet")
+	cmd.Dir = path
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git reset: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// IsOrphanedCheckoutOf reports whether path holds an unregistered checkout of branch in
+// this repository. When the administrative files are gone the branch cannot be read back,
+// so the checkout is assumed to belong to the branch whose path it occupies.
+func (m *Manager) IsOrphanedCheckoutOf(path string, branch string) bool {
+	commonDir, err := gitCommonDir()
+	if err != nil {
+		return false
+	}
+	gitDir, ok := ownedGitDir(path, commonDir)
+	if !ok {
+		return false
+	}
+
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return false
+	}
+	for _, wt := range worktrees {
+		if filepath.Clean(wt.Path) == filepath.Clean(path) {
+			return false
+		}
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if os.IsNotExist(err) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(head)) == "ref: refs/heads/"+branch
+}
+
+// PurgeOrphanedDirectory deletes an orphaned directory and everything below it
+func (m *Manager) PurgeOrphanedDirectory(path string) error {
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
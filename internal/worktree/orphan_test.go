@@ -0,0 +1,180 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCollectOrphanedPaths(t *testing.T) {
+	wkitRoot := t.TempDir()
+
+	// Registered worktree nested under a branch prefix directory
+	mustMkdir(t, filepath.Join(wkitRoot, "feature", "registered"))
+	// Orphaned checkout next to it
+	mustMkdir(t, filepath.Join(wkitRoot, "feature", "lost"))
+	mustWriteFile(t, filepath.Join(wkitRoot, "feature", "lost", ".git"), "gitdir: /repo/.git/worktrees/lost\n")
+	// Leftover directory without a checkout
+	mustMkdir(t, filepath.Join(wkitRoot, "leftover", "node_modules"))
+	// Plain files are ignored
+	mustWriteFile(t, filepath.Join(wkitRoot, "README"), "not a worktree")
+
+	registered := map[string]bool{
+		filepath.Join(wkitRoot, "feature", "registered"): true,
+	}
+
+	var paths []string
+	if err := collectOrphanedPaths(wkitRoot, registered, &paths); err != nil {
+		t.Fatalf("collectOrphanedPaths() failed: %v", err)
+	}
+	sort.Strings(paths)
+
+	expected := []string{
+		filepath.Join(wkitRoot, "feature", "lost"),
+		filepath.Join(wkitRoot, "leftover"),
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("paths[%d] = %s, want %s", i, paths[i], expected[i])
+		}
+	}
+}
+
+func TestReadGitDirFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, ok := readGitDirFile(dir); ok {
+		t.Error("Expected no gitdir for directory without .git file")
+	}
+
+	mustWriteFile(t, filepath.Join(dir, ".git"), "gitdir: ../admin\n")
+	gitDir, ok := readGitDirFile(dir)
+	if !ok {
+		t.Fatal("Expected gitdir to be read")
+	}
+	if expected := filepath.Join(dir, "..", "admin"); gitDir != expected {
+		t.Errorf("gitdir = %s, want %s", gitDir, expected)
+	}
+}
+
+func TestOwnedGitDir(t *testing.T) {
+	dir := resolveSymlinks(t.TempDir())
+	commonDir := filepath.Join(dir, "repo", ".git")
+	mustMkdir(t, filepath.Join(commonDir, "worktrees", "ours"))
+	mustMkdir(t, filepath.Join(dir, "other", ".git", "worktrees", "theirs"))
+
+	tests := []struct {
+		name     string
+		gitDir   string
+		expected bool
+	}{
+		{"checkout of this repository", filepath.Join(commonDir, "worktrees", "ours"), true},
+		{"admin files already pruned", filepath.Join(commonDir, "worktrees", "gone"), true},
+		{"checkout of another repository", filepath.Join(dir, "other", ".git", "worktrees", "theirs"), false},
+		{"main git dir", commonDir, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout := filepath.Join(t.TempDir(), "checkout")
+			mustMkdir(t, checkout)
+			mustWriteFile(t, filepath.Join(checkout, ".git"), "gitdir: "+tt.gitDir+"\n")
+
+			if _, ok := ownedGitDir(checkout, commonDir); ok != tt.expected {
+				t.Errorf("ownedGitDir() = %v, want %v", ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBranchFromWkitPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		wkitRoot string
+		path     string
+		expected string
+	}{
+		{
+			name:     "simple branch",
+			wkitRoot: "/repo/.git/.wkit-worktrees",
+			path:     "/repo/.git/.wkit-worktrees/feature",
+			expected: "feature",
+		},
+		{
+			name:     "nested branch",
+			wkitRoot: "/repo/.git/.wkit-worktrees",
+			path:     "/repo/.git/.wkit-worktrees/feature/foo",
+			expected: "feature/foo",
+		},
+		{
+			name:     "outside wkit root",
+			wkitRoot: "/repo/.git/.wkit-worktrees",
+			path:     "/tmp/feature",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BranchFromWkitPath(tt.wkitRoot, tt.path)
+			if result != tt.expected {
+				t.Errorf("BranchFromWkitPath() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "a"), "12345")
+	mustMkdir(t, filepath.Join(dir, "sub"))
+	mustWriteFile(t, filepath.Join(dir, "sub", "b"), "123")
+
+	size, err := DirSize(dir)
+	if err != nil {
+		t.Fatalf("DirSize() failed: %v", err)
+	}
+	if size != 8 {
+		t.Errorf("DirSize() = %d, want 8", size)
+	}
+}
+
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+}
+
+func mustWriteFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestWorktreeSize(t *testing.T) {
+	root := t.TempDir()
+	mustMkdir(t, filepath.Join(root, ".git", "objects"))
+	mustWriteFile(t, filepath.Join(root, ".git", "objects", "pack"), strings.Repeat("x", 1000))
+	mustMkdir(t, filepath.Join(root, "src"))
+	mustWriteFile(t, filepath.Join(root, "src", "main.go"), "12345")
+	mustWriteFile(t, filepath.Join(root, "README"), "123")
+	// A worktree nested inside the main worktree is not part of its size
+	mustMkdir(t, filepath.Join(root, "nested"))
+	mustWriteFile(t, filepath.Join(root, "nested", ".git"), "gitdir: /elsewhere\n")
+	mustWriteFile(t, filepath.Join(root, "nested", "big"), strings.Repeat("x", 500))
+
+	size, err := WorktreeSize(root)
+	if err != nil {
+		t.Fatalf("WorktreeSize() failed: %v", err)
+	}
+	if size != 8 {
+		t.Errorf("WorktreeSize() = %d, want 8", size)
+	}
+}
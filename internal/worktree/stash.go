@@ -0,0 +1,114 @@
+package worktree
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stash is an entry of 'git stash list'. Stashes are shared by all worktrees of a repository,
+// so the branch recorded in the stash message is the only link to the worktree that made it.
+type Stash struct {
+	Index   int       `json:"index" yaml:"index"`
+	Ref     string    `json:"ref" yaml:"ref"`
+	Hash    string    `json:"hash" yaml:"hash"`
+	Branch  string    `json:"branch" yaml:"branch"` // empty for stashes made on a detached HEAD
+	Message string    `json:"message" yaml:"message"`
+	Created time.Time `json:"created" yaml:"created"`
+}
+
+// ListStashes lists the stashes of the repository, newest first
+func (m *Manager) ListStashes() ([]Stash, error) {
+	cmd := exec.Command("git", "stash", "list", "--format=%gd%x09%H%x09%ct%x09%gs")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git stash list: %w", err)
+	}
+
+	return parseStashList(string(output))
+}
+
+// parseStashList parses the output of 'git stash list --format=%gd%x09%H%x09%ct%x09%gs'
+func parseStashList(output string) ([]Stash, error) {
+	var stashes []Stash
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("malformed stash line %q", line)
+		}
+
+		var index int
+		if _, err := fmt.Sscanf(fields[0], "stash@{%d}", &index); err != nil {
+			return nil, fmt.Errorf("failed to parse stash ref %q: %w", fields[0], err)
+		}
+		created, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stash time %q: %w", fields[2], err)
+		}
+
+		branch, message := parseStashSubject(fields[3])
+		stashes = append(stashes, Stash{
+			Index:   index,
+			Ref:     fields[0],
+			Hash:    fields[1],
+			Branch:  branch,
+			Message: message,
+			Created: time.Unix(created, 0),
+		})
+	}
+	return stashes, nil
+}
+
+// parseStashSubject splits a stash subject such as "WIP on feature: abc1234 subject"
+// or "On feature: message" into the branch and the message
+func parseStashSubject(subject string) (branch string, message string) {
+	rest, ok := strings.CutPrefix(subject, "WIP on ")
+	if !ok {
+		rest, ok = strings.CutPrefix(subject, "On ")
+	}
+	if !ok {
+		return "", subject
+	}
+
+	branch, message, ok = strings.Cut(rest, ": ")
+	if !ok {
+		return "", subject
+	}
+	if branch == "(no branch)" {
+		branch = ""
+	}
+	return branch, message
+}
+
+// StashRef returns the ref of the stash at index, e.g. "stash@{2}"
+func StashRef(index int) string {
+	return fmt.Sprintf("stash@{%d}", index)
+}
+
+// ApplyStash applies a stash in the given worktree, keeping it in the stash list. The stash
+// is applied by its commit hash, so a stash pushed or dropped since it was listed cannot
+// shift a different one into its index.
+func (m *Manager) ApplyStash(worktreePath string, stash Stash) error {
+	cmd := exec.Command("git", "stash", "apply", stash.Hash)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute git stash apply: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CountStashesByBranch counts stashes per originating branch
+func CountStashesByBranch(stashes []Stash) map[string]int {
+	counts := make(map[string]int)
+	for _, stash := range stashes {
+		counts[stash.Branch]++
+	}
+	return counts
+}
@@ -0,0 +1,190 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchWorktrees calls onChange with the index of a worktree in worktrees once files in it
+// have stopped changing for debounce. Changes to its index and HEAD in the git dir count too.
+// onChange is never called concurrently for the same worktree; changes made while it runs
+// cause one more call once it returns. Directories that cannot be watched, e.g. because the
+// inotify watch limit is reached, are reported to onWarning and skipped.
+// It blocks until ctx is done.
+func WatchWorktrees(ctx context.Context, worktrees []Worktree, debounce time.Duration, onChange func(index int), onWarning func(err error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, wt := range worktrees {
+		addWatchTree(watcher, wt.Path, onWarning)
+		if gitDir, err := worktreeGitDir(wt.Path); err == nil {
+			// Staging and committing only touch the git dir
+			if err := watcher.Add(gitDir); err != nil {
+				onWarning(fmt.Errorf("failed to watch %s: %w", gitDir, err))
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	timers := make(map[int]*time.Timer)
+	running := make(map[int]bool)
+	pending := make(map[int]bool)
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	// refresh runs onChange for index, or leaves it to the call already running
+	refresh := func(index int) {
+		mu.Lock()
+		if running[index] {
+			pending[index] = true
+			mu.Unlock()
+			return
+		}
+		running[index] = true
+		mu.Unlock()
+
+		for {
+			if ctx.Err() == nil {
+				onChange(index)
+			}
+
+			mu.Lock()
+			again := pending[index] && ctx.Err() == nil
+			pending[index] = false
+			running[index] = again
+			mu.Unlock()
+			if !again {
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			index := owningWorktree(worktrees, event.Name)
+			if index < 0 {
+				continue
+			}
+			// New directories are not watched automatically
+			if event.Has(fsnotify.Create) && !isUnderGitDir(worktrees[index].Path, event.Name) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !isIgnored(worktrees[index].Path, event.Name) {
+					addWatchTree(watcher, event.Name, onWarning)
+				}
+			}
+
+			mu.Lock()
+			if timer, ok := timers[index]; ok {
+				timer.Reset(debounce)
+			} else {
+				timers[index] = time.AfterFunc(debounce, func() { refresh(index) })
+			}
+			mu.Unlock()
+		}
+	}
+}
+
+// addWatchTree watches root and every directory below it except .git, nested checkouts and
+// directories ignored by git, such as node_modules. Directories that cannot be watched are
+// reported to onWarning.
+func addWatchTree(watcher *fsnotify.Watcher, root string, onWarning func(err error)) {
+	ignored := ignoredDirs(root)
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Directories can disappear while walking
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" || ignored[path] || (path != root && fileExists(filepath.Join(path, ".git"))) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			onWarning(fmt.Errorf("failed to watch %s: %w", path, err))
+		}
+		return nil
+	})
+}
+
+// ignoredDirs returns the directories below dir that git ignores, or none when dir is not
+// in a git worktree
+func ignoredDirs(dir string) map[string]bool {
+	cmd := exec.Command("git", "ls-files", "--others", "--ignored", "--exclude-standard", "--directory", "-z")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	ignored := make(map[string]bool)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if rel, ok := strings.CutSuffix(entry, "/"); ok {
+			ignored[filepath.Join(dir, rel)] = true
+		}
+	}
+	return ignored
+}
+
+// isIgnored reports whether git ignores path in the worktree at worktreePath
+func isIgnored(worktreePath string, path string) bool {
+	cmd := exec.Command("git", "check-ignore", "--quiet", path)
+	cmd.Dir = worktreePath
+	return cmd.Run() == nil
+}
+
+// owningWorktree returns the index of the worktree that path belongs to, preferring the
+// deepest one so that worktrees nested in the main worktree are told apart, or -1
+func owningWorktree(worktrees []Worktree, path string) int {
+	best := -1
+	for i, wt := range worktrees {
+		if !isPathWithin(wt.Path, path) {
+			if gitDir, err := worktreeGitDir(wt.Path); err != nil || !isPathWithin(gitDir, path) {
+				continue
+			}
+			// Events in a linked worktree's admin dir belong to that worktree alone
+			return i
+		}
+		if best < 0 || len(wt.Path) > len(worktrees[best].Path) {
+			best = i
+		}
+	}
+	return best
+}
+
+func isUnderGitDir(worktreePath string, path string) bool {
+	return isPathWithin(filepath.Join(worktreePath, ".git"), path)
+}
+
+func isPathWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,161 @@
+package worktree
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchWorktrees(t *testing.T) {
+	var worktrees []Worktree
+	for _, name := range []string{"a", "b"} {
+		path := filepath.Join(t.TempDir(), name)
+		mustMkdir(t, filepath.Join(path, ".git"))
+		mustMkdir(t, filepath.Join(path, "src"))
+		worktrees = append(worktrees, Worktree{Path: path, Branch: name})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan int, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchWorktrees(ctx, worktrees, 50*time.Millisecond, func(index int) { changed <- index }, func(err error) { t.Errorf("Unexpected warning: %v", err) })
+	}()
+
+	// Give the watcher time to register before changing files
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		mustWriteFile(t, filepath.Join(worktrees[1].Path, "src", "main.go"), "package main\n")
+	}
+
+	select {
+	case index := <-changed:
+		if index != 1 {
+			t.Errorf("onChange(%d), want onChange(1)", index)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called")
+	}
+
+	// Rapid writes are debounced into a single call
+	select {
+	case index := <-changed:
+		t.Errorf("Unexpected second onChange(%d)", index)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchWorktrees() failed: %v", err)
+	}
+}
+
+func TestOwningWorktree(t *testing.T) {
+	root := t.TempDir()
+	mustMkdir(t, filepath.Join(root, ".git"))
+	linked := filepath.Join(root, ".git", ".wkit-worktrees", "feature")
+	mustMkdir(t, linked)
+	adminDir := filepath.Join(root, ".git", "worktrees", "feature")
+	mustMkdir(t, adminDir)
+	mustWriteFile(t, filepath.Join(linked, ".git"), "gitdir: "+adminDir+"\n")
+
+	worktrees := []Worktree{{Path: root}, {Path: linked}}
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{path: filepath.Join(root, "main.go"), expected: 0},
+		{path: filepath.Join(root, ".git", "index"), expected: 0},
+		{path: filepath.Join(linked, "main.go"), expected: 1},
+		{path: filepath.Join(adminDir, "index"), expected: 1},
+		{path: filepath.Join(t.TempDir(), "elsewhere"), expected: -1},
+	}
+
+	for _, tt := range tests {
+		if index := owningWorktree(worktrees, tt.path); index != tt.expected {
+			t.Errorf("owningWorktree(%s) = %d, want %d", tt.path, index, tt.expected)
+		}
+	}
+}
+
+func TestWatchWorktreesSerializesRefreshes(t *testing.T) {
+	path := t.TempDir()
+	mustMkdir(t, filepath.Join(path, ".git"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var active, overlaps, calls atomic.Int32
+	started := make(chan struct{}, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchWorktrees(ctx, []Worktree{{Path: path}}, 20*time.Millisecond, func(index int) {
+			if active.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			calls.Add(1)
+			started <- struct{}{}
+			time.Sleep(200 * time.Millisecond)
+			active.Add(-1)
+		}, func(err error) { t.Errorf("Unexpected warning: %v", err) })
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	mustWriteFile(t, filepath.Join(path, "a.txt"), "1\n")
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called")
+	}
+
+	// Changes during a refresh lead to exactly one more refresh, after it
+	mustWriteFile(t, filepath.Join(path, "a.txt"), "2\n")
+	time.Sleep(50 * time.Millisecond)
+	mustWriteFile(t, filepath.Join(path, "a.txt"), "3\n")
+	time.Sleep(600 * time.Millisecond)
+
+	if overlaps.Load() > 0 {
+		t.Error("onChange ran concurrently for the same worktree")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("onChange called %d times, want 2", calls.Load())
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchWorktrees() failed: %v", err)
+	}
+}
+
+func TestIgnoredDirs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping TestIgnoredDirs: git not available")
+	}
+
+	path := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", path).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, output)
+	}
+	mustWriteFile(t, filepath.Join(path, ".gitignore"), "node_modules/\n")
+	mustMkdir(t, filepath.Join(path, "node_modules", "pkg"))
+	mustWriteFile(t, filepath.Join(path, "node_modules", "pkg", "index.js"), "\n")
+	mustMkdir(t, filepath.Join(path, "src"))
+	mustWriteFile(t, filepath.Join(path, "src", "main.go"), "package main\n")
+
+	ignored := ignoredDirs(path)
+	if !ignored[filepath.Join(path, "node_modules")] || len(ignored) != 1 {
+		t.Errorf("ignoredDirs() = %v, want only node_modules", ignored)
+	}
+	if !isIgnored(path, filepath.Join(path, "node_modules")) || isIgnored(path, filepath.Join(path, "src")) {
+		t.Error("isIgnored() disagrees with .gitignore")
+	}
+
+	if ignored := ignoredDirs(t.TempDir()); len(ignored) != 0 {
+		t.Errorf("ignoredDirs() outside a repository = %v, want none", ignored)
+	}
+}
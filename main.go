@@ -27,6 +27,21 @@ func init() {
 	rootCmd.AddCommand(cmd.NewCleanCmd())
 	rootCmd.AddCommand(cmd.NewSyncCmd())
 	rootCmd.AddCommand(cmd.NewRootCmd())
+	rootCmd.AddCommand(cmd.NewDoctorCmd())
+	rootCmd.AddCommand(cmd.NewAdoptCmd())
+	rootCmd.AddCommand(cmd.NewRelocateCmd())
+	rootCmd.AddCommand(cmd.NewStashCmd())
+	rootCmd.AddCommand(cmd.NewLockCmd())
+	rootCmd.AddCommand(cmd.NewUnlockCmd())
+	rootCmd.AddCommand(cmd.NewUICmd())
+	rootCmd.AddCommand(cmd.NewJumpCmd())
+	rootCmd.AddCommand(cmd.NewCurrentCmd())
+	rootCmd.AddCommand(cmd.NewExecCmd())
+	rootCmd.AddCommand(cmd.NewShellInitCmd())
+	rootCmd.AddCommand(cmd.NewCompletionCmd())
+
+	// completion is provided by wkit completion, which documents the dynamic completions
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
 func main() {